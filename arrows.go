@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ArrowSpec описание стрелки из параметра запроса
+type ArrowSpec struct {
+	Stickers []StickerRef // Начало, конец и (необязательно) элемент, через который проходит дуга
+	Double   bool         // Стрелка с двумя наконечниками
+	Curve    float64      // Изгиб стрелки относительно её длины (0 — прямая)
+	Color    rune         // Цвет стрелки
+	Auto     string       // Тип автоматических стрелок перестановки (all, corners, edges)
+}
+
+// Arrow стрелка, готовая к построению
+type Arrow struct {
+	From, To Point   // Начало и конец стрелки
+	Via      *Point  // Точка, через которую проходит дуга
+	Double   bool    // Стрелка с двумя наконечниками
+	Curve    float64 // Изгиб стрелки относительно её длины (0 — прямая)
+	Color    rune    // Цвет стрелки
+}
+
+var (
+	stickerRefRegexp    = regexp.MustCompile(`([UDFBLR])(\d+)`)
+	arrowStickersRegexp = regexp.MustCompile(`^([UDFBLR]\d+){2,3}$`)
+)
+
+// ParseArrows парсит список стрелок вида U0U2U8-d-c0.3-R,U6U0.
+// После номеров элементов через дефис указываются опции:
+// s/d — один или два наконечника, c<число> — изгиб, буква — цвет из colorMapRGBA
+func ParseArrows(pArrows string) ([]ArrowSpec, error) {
+	var specs []ArrowSpec
+	if pArrows == "" {
+		return specs, nil
+	}
+
+	for _, pArrow := range strings.Split(pArrows, ",") {
+		parts := strings.Split(pArrow, "-")
+		spec := ArrowSpec{Color: 'K'}
+
+		// Извлекаем элементы стрелки (auto — стрелки перестановки вычисляются по состоянию кубика)
+		if parts[0] == "auto" {
+			spec.Auto = "all"
+		} else if !arrowStickersRegexp.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid arrow %q: expected 2 or 3 stickers like U0U2", pArrow)
+		}
+		for _, ref := range stickerRefRegexp.FindAllStringSubmatch(parts[0], -1) {
+			index, err := strconv.Atoi(ref[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid arrow sticker %q: %w", ref[0], err)
+			}
+			spec.Stickers = append(spec.Stickers, StickerRef{Face: rune(ref[1][0]), Index: index})
+		}
+
+		// Извлекаем опции стрелки. Минус отрицательного изгиба (c-0.3) совпадает с разделителем опций
+		options := parts[1:]
+		for i := 0; i < len(options); i++ {
+			option := options[i]
+			if option == "c" && i+1 < len(options) {
+				i++
+				option = "c-" + options[i]
+			}
+			switch {
+			case option == "s":
+				spec.Double = false
+			case option == "d":
+				spec.Double = true
+			case spec.Auto != "" && (option == "all" || option == "corners" || option == "edges"):
+				spec.Auto = option
+			case strings.HasPrefix(option, "c"):
+				curve, err := strconv.ParseFloat(option[1:], 64)
+				if err != nil || math.IsNaN(curve) || math.IsInf(curve, 0) {
+					return nil, fmt.Errorf("invalid arrow curvature %q", option)
+				}
+				spec.Curve = curve
+			case len(option) == 1 && colorMapRGBA[rune(option[0])] != "":
+				spec.Color = rune(option[0])
+			default:
+				return nil, fmt.Errorf("invalid arrow option %q", option)
+			}
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// ResolveArrows переводит ссылки на элементы в координаты с помощью функции locate
+func ResolveArrows(specs []ArrowSpec, locate func(ref StickerRef) (StickerFrame, bool)) ([]Arrow, error) {
+	arrows := make([]Arrow, 0, len(specs))
+	for _, spec := range specs {
+		points := make([]Point, len(spec.Stickers))
+		for i, ref := range spec.Stickers {
+			frame, ok := locate(ref)
+			if !ok {
+				return nil, fmt.Errorf("unknown sticker %c%d for arrow", ref.Face, ref.Index)
+			}
+			points[i] = frame.Center
+		}
+
+		arrow := Arrow{From: points[0], To: points[1], Double: spec.Double, Curve: spec.Curve, Color: spec.Color}
+		if len(points) == 3 {
+			arrow.Via = &points[2]
+		}
+		arrows = append(arrows, arrow)
+	}
+	return arrows, nil
+}
+
+// GenerateArrows генерирует слой стрелок. step — шаг сетки элементов,
+// от него зависят толщина линий и размер наконечников
+func GenerateArrows(builder *strings.Builder, arrows []Arrow, step float64) {
+	if len(arrows) == 0 {
+		return
+	}
+
+	// Наконечники задаются маркерами, отдельными для каждого цвета
+	builder.WriteString("\r\n\t<defs>")
+	markers := make(map[rune]bool)
+	for _, arrow := range arrows {
+		if markers[arrow.Color] {
+			continue
+		}
+		markers[arrow.Color] = true
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<marker id=\"arrow-%c\" viewBox=\"0 0 10 10\" refX=\"5\" refY=\"5\" markerWidth=\"3.5\" markerHeight=\"3.5\" orient=\"auto-start-reverse\">"+
+			"<path d=\"M0 0L10 5L0 10z\" style=\"fill: %s\"/></marker>", arrow.Color, colorMapRGBA[arrow.Color]))
+	}
+	builder.WriteString("\r\n\t</defs>")
+
+	strokeWidth := step * 0.08
+	shrink := step * 0.2
+
+	// Начало группы
+	builder.WriteString("\r\n\t<g id=\"arrows\">")
+	for i, arrow := range arrows {
+		from, to := arrow.From, arrow.To
+
+		// Контрольная точка квадратичной кривой
+		var control *Point
+		if arrow.Via != nil {
+			// Кривая проходит через точку Via в середине
+			control = &Point{X: 2*arrow.Via.X - (from.X+to.X)/2, Y: 2*arrow.Via.Y - (from.Y+to.Y)/2}
+		} else if arrow.Curve != 0 {
+			length := math.Hypot(to.X-from.X, to.Y-from.Y)
+			if length > 0 {
+				nx, ny := -(to.Y-from.Y)/length, (to.X-from.X)/length
+				control = &Point{X: (from.X+to.X)/2 + nx*arrow.Curve*length, Y: (from.Y+to.Y)/2 + ny*arrow.Curve*length}
+			}
+		}
+
+		// Укорачиваем стрелку, чтобы наконечники не закрывали центры элементов
+		towardFrom, towardTo := to, from
+		if control != nil {
+			towardFrom, towardTo = *control, *control
+		}
+		from = moveToward(from, towardFrom, shrink)
+		to = moveToward(to, towardTo, shrink)
+
+		d := fmt.Sprintf("M%.2f %.2fL%.2f %.2f", from.X, from.Y, to.X, to.Y)
+		if control != nil {
+			d = fmt.Sprintf("M%.2f %.2fQ%.2f %.2f %.2f %.2f", from.X, from.Y, control.X, control.Y, to.X, to.Y)
+		}
+
+		markerStart := ""
+		if arrow.Double {
+			markerStart = fmt.Sprintf(" marker-start=\"url(#arrow-%c)\"", arrow.Color)
+		}
+
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"arrow-%d\" d=\"%s\"%s marker-end=\"url(#arrow-%c)\" style=\"fill: none; stroke: %s; stroke-width: %.2f; stroke-linecap: round\"/>",
+			i+1, d, markerStart, arrow.Color, colorMapRGBA[arrow.Color], strokeWidth))
+	}
+	// Закрытие группы
+	builder.WriteString("\r\n\t</g>")
+}
+
+// moveToward сдвигает точку p в сторону точки target на расстояние distance
+func moveToward(p, target Point, distance float64) Point {
+	length := math.Hypot(target.X-p.X, target.Y-p.Y)
+	if length == 0 {
+		return p
+	}
+	k := math.Min(distance, length/2) / length
+	return Point{X: p.X + (target.X-p.X)*k, Y: p.Y + (target.Y-p.Y)*k}
+}
+
+// SetArrows парсит стрелки для плоского вида. Элементы верхней стороны
+// (центральная часть картинки) обозначаются буквой U.
+// Автоматические стрелки перестановки строятся по состоянию кубика state
+func (cube *FlatCube) SetArrows(pArrows string, state *CubeState) error {
+	parsed, err := ParseArrows(pArrows)
+	if err != nil {
+		return err
+	}
+
+	// Разворачиваем автоматические стрелки в обычные
+	var specs []ArrowSpec
+	for _, spec := range parsed {
+		if spec.Auto == "" {
+			specs = append(specs, spec)
+			continue
+		}
+		if state == nil {
+			return fmt.Errorf("automatic arrows require a cube state (alg or case)")
+		}
+		auto, err := state.PermutationArrows(spec.Auto, spec)
+		if err != nil {
+			return err
+		}
+		specs = append(specs, auto...)
+	}
+
+	cube.Arrows, err = ResolveArrows(specs, cube.stickerFrame)
+	return err
+}
+
+// SetArrows парсит стрелки для изометрического вида. Элементы видимых сторон
+// обозначаются буквами F, U и R
+func (cube *IsometricCube) SetArrows(pArrows string) error {
+	specs, err := ParseArrows(pArrows)
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if spec.Auto != "" {
+			return fmt.Errorf("automatic arrows are only supported in the flat view")
+		}
+	}
+
+	cube.Arrows, err = ResolveArrows(specs, cube.stickerFrame)
+	return err
+}
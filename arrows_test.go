@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArrows(t *testing.T) {
+	tests := []struct {
+		arrows string
+		want   []ArrowSpec
+	}{
+		{"", []ArrowSpec(nil)},
+		{"U0U2", []ArrowSpec{{Stickers: []StickerRef{{'U', 0}, {'U', 2}}, Color: 'K'}}},
+		{"U0U2U8-d-c0.3-R,F6F0", []ArrowSpec{
+			{Stickers: []StickerRef{{'U', 0}, {'U', 2}, {'U', 8}}, Double: true, Curve: 0.3, Color: 'R'},
+			{Stickers: []StickerRef{{'F', 6}, {'F', 0}}, Color: 'K'},
+		}},
+		{"U1U7-c-0.5", []ArrowSpec{{Stickers: []StickerRef{{'U', 1}, {'U', 7}}, Curve: -0.5, Color: 'K'}}},
+		{"auto-edges-G", []ArrowSpec{{Auto: "edges", Color: 'G'}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arrows, func(t *testing.T) {
+			got, err := ParseArrows(tt.arrows)
+			if err != nil {
+				t.Fatalf("ParseArrows(%q): %v", tt.arrows, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseArrows(%q) = %+v, want %+v", tt.arrows, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArrowsRejectsBadInput(t *testing.T) {
+	for _, arrows := range []string{
+		"U0",
+		"U0U1U2U3",
+		"U0-U2",
+		"Q0U2",
+		"U0U2-x",
+		"U0U2-cabc",
+		"U0U2-cNaN",
+		"U0U2-cInf",
+		"U0U2-c-Inf",
+		"U99999999999999999999U2",
+		"auto-diagonals",
+	} {
+		if _, err := ParseArrows(arrows); err == nil {
+			t.Errorf("ParseArrows(%q) returned no error", arrows)
+		}
+	}
+}
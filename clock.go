@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type Clock struct {
+	Front  [9]int  // Положение стрелок передней стороны (часы 0–11) по строкам слева направо
+	Back   [9]int  // Положение стрелок задней стороны, как её видно сзади
+	Pins   [4]bool // Штырьки UL, UR, DL, DR, как их видно спереди: true — нажат вверх (к передней стороне)
+	Colors [5]rune // Цвета: передний корпус, задний корпус, циферблаты, стрелки, поднятые штырьки
+	Rotate float64 // Угол поворота картинки в градусах
+}
+
+// Цвета по умолчанию: синий передний корпус, голубой задний, белые циферблаты, чёрные стрелки, жёлтые штырьки
+var clockDefaultColors = [5]rune{'B', 'C', 'W', 'K', 'Y'}
+
+// Параметры построения часов (в точках)
+const (
+	clockSpacing = 72 // Расстояние между центрами соседних циферблатов
+	clockDial    = 26 // Радиус циферблата
+	clockTick    = 31 // Радиус окружности с метками часов
+	clockBody    = 36 // Выступ корпуса за центры крайних циферблатов
+	clockLobe    = 42 // Радиус выступов корпуса вокруг угловых циферблатов
+	clockPin     = 8  // Радиус штырька
+	clockBorder  = 3  // Толщина обводки корпуса
+	clockGap     = 24 // Расстояние между сторонами на виде с двух сторон
+)
+
+// ParseClockParams парсит состояние часов вида {front}-{back}-{pins}
+// и цвета вида {front}-{back}-{dials}-{hands}-{pins}
+func ParseClockParams(pState, pColors string) (Clock, error) {
+	clock := Clock{Colors: clockDefaultColors}
+
+	parts := strings.Split(strings.ToUpper(pState), "-")
+	if len(parts) > 3 {
+		return Clock{}, fmt.Errorf("invalid state: expected {front}-{back}-{pins}")
+	}
+
+	// Стрелки: по одному символу на циферблат (0–9, A — 10, B — 11) или один символ на всю сторону
+	var err error
+	if clock.Front, err = parseClockDials("front", parts[0]); err != nil {
+		return Clock{}, err
+	}
+	if len(parts) > 1 {
+		if clock.Back, err = parseClockDials("back", parts[1]); err != nil {
+			return Clock{}, err
+		}
+	}
+
+	// Штырьки: U — вверх, D — вниз, по одному символу на штырёк или один на все
+	if len(parts) > 2 {
+		pins := []rune(parts[2])
+		if len(pins) == 1 {
+			pins = []rune(strings.Repeat(parts[2], 4))
+		}
+		if len(pins) != 4 {
+			return Clock{}, fmt.Errorf("invalid pins %q: expected 4 letters U or D", parts[2])
+		}
+		for i, r := range pins {
+			switch r {
+			case 'U':
+				clock.Pins[i] = true
+			case 'D':
+				clock.Pins[i] = false
+			default:
+				return Clock{}, fmt.Errorf("invalid pins %q: expected 4 letters U or D", parts[2])
+			}
+		}
+	}
+
+	// Цвета (по одной букве)
+	for i, color := range strings.Split(strings.ToUpper(pColors), "-") {
+		if i < len(clock.Colors) && len(color) > 0 {
+			clock.Colors[i] = rune(color[0])
+		}
+	}
+
+	return clock, nil
+}
+
+// parseClockDials парсит положения стрелок одной стороны
+func parseClockDials(name, pDials string) ([9]int, error) {
+	var dials [9]int
+	runes := []rune(pDials)
+	if len(runes) == 1 {
+		runes = []rune(strings.Repeat(pDials, 9))
+	}
+	if len(runes) != 9 {
+		return dials, fmt.Errorf("invalid %s dials %q: expected 9 hours", name, pDials)
+	}
+	for i, r := range runes {
+		switch {
+		case r >= '0' && r <= '9':
+			dials[i] = int(r - '0')
+		case r == 'A' || r == 'B':
+			dials[i] = int(r-'A') + 10
+		default:
+			return dials, fmt.Errorf("invalid %s dials %q: hours must be 0-9, A (10) or B (11)", name, pDials)
+		}
+	}
+	return dials, nil
+}
+
+// GenerateClock генерирует SVG картинку часов: только переднюю сторону
+// или обе стороны рядом (задняя — справа, как её видно после поворота часов вокруг вертикальной оси)
+func GenerateClock(clock Clock, both bool) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Размер одной стороны с обводкой
+	side := 2*(clockSpacing+clockLobe+clockBorder) + 2
+	width := side
+	if both {
+		width = 2*side + clockGap
+	}
+
+	// Задняя сторона: штырьки отражены слева направо и нажаты в обратную сторону
+	var backPins [4]bool
+	for i, pin := range clock.Pins {
+		backPins[i^1] = !pin
+	}
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, float64(width), float64(side), clock.Rotate)
+
+	generateClockSide(&builder, "front", float64(side)/2, float64(side)/2, clock.Front, clock.Pins, clock.Colors[0], clock)
+	if both {
+		generateClockSide(&builder, "back", float64(side+clockGap)+float64(side)/2, float64(side)/2, clock.Back, backPins, clock.Colors[1], clock)
+	}
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, clock.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// generateClockSide рисует одну сторону часов с центром в точке (cx, cy)
+func generateClockSide(builder *strings.Builder, id string, cx, cy float64, dials [9]int, pins [4]bool, body rune, clock Clock) {
+	builder.WriteString(fmt.Sprintf("\r\n<g id=\"%s\" transform=\"translate(%.2f %.2f)\">", id, cx, cy))
+
+	// Корпус: квадрат с выступами вокруг угловых циферблатов. Сначала рисуем обводку всех частей,
+	// затем заливку поверх, чтобы внутри корпуса линии не было видно
+	corners := [4]Point{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: 1, Y: 1}}
+	colorBody := colorMapRGBA[body]
+	for _, style := range []string{
+		fmt.Sprintf("fill: %s; stroke: %s; stroke-width: %d", colorMapRGBA['K'], colorMapRGBA['K'], 2*clockBorder),
+		fmt.Sprintf("fill: %s", colorBody),
+	} {
+		builder.WriteString(fmt.Sprintf("\r\n\t<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"%d\" style=\"%s\"/>",
+			-clockSpacing-clockBody, -clockSpacing-clockBody, 2*(clockSpacing+clockBody), 2*(clockSpacing+clockBody), clockBody, style))
+		for _, c := range corners {
+			builder.WriteString(fmt.Sprintf("\r\n\t<circle cx=\"%.0f\" cy=\"%.0f\" r=\"%d\" style=\"%s\"/>",
+				c.X*clockSpacing, c.Y*clockSpacing, clockLobe, style))
+		}
+	}
+
+	// Циферблаты со стрелками
+	colorHand := colorMapRGBA[clock.Colors[3]]
+	for i, hour := range dials {
+		x, y := float64(i%3-1)*clockSpacing, float64(i/3-1)*clockSpacing
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s-%d\" transform=\"translate(%.0f %.0f)\">", id, i+1, x, y))
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<circle r=\"%d\" style=\"fill: %s; stroke: %s; stroke-width: 2\"/>",
+			clockDial, colorMapRGBA[clock.Colors[2]], colorMapRGBA['K']))
+
+		// Метки часов: метка двенадцати часов красная
+		for h := 0; h < 12; h++ {
+			color := colorHand
+			if h == 0 {
+				color = colorMapRGBA['R']
+			}
+			a := float64(h) * math.Pi / 6
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<circle cx=\"%.2f\" cy=\"%.2f\" r=\"2.5\" style=\"fill: %s\"/>",
+				clockTick*math.Sin(a), -clockTick*math.Cos(a), color))
+		}
+
+		// Стрелка
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"M0 -22L4.5 -2L0 5L-4.5 -2z\" transform=\"rotate(%d)\" style=\"fill: %s\"/>", hour*30, colorHand))
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<circle r=\"4\" style=\"fill: %s\"/>", colorHand))
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	// Штырьки между циферблатами: поднятый штырёк закрашен, опущенный — цвета корпуса
+	for i, c := range corners {
+		color := colorBody
+		if pins[i] {
+			color = colorMapRGBA[clock.Colors[4]]
+		}
+		builder.WriteString(fmt.Sprintf("\r\n\t<circle id=\"%s-pin-%d\" cx=\"%.0f\" cy=\"%.0f\" r=\"%d\" style=\"fill: %s; stroke: %s; stroke-width: 2\"/>",
+			id, i+1, c.X*clockSpacing/2, c.Y*clockSpacing/2, clockPin, color, colorMapRGBA['K']))
+	}
+
+	builder.WriteString("\r\n</g>")
+}
@@ -23,9 +23,41 @@ var colorMapRGBA = map[rune]string{
 	'W': RGBAtoHex(223, 223, 223, 0), // Белый
 	'O': RGBAtoHex(239, 108, 0, 0),   // Оранжевый
 	// 'O': RGBAtoHex(255, 164, 13, 0),  // Оранжевый
-	'X': RGBAtoHex(86, 86, 86, 0), // Серый
-	'K': RGBAtoHex(0, 0, 0, 0),    // Чёрный
-	'T': "transparent",            // Прозрачный
+	'P': RGBAtoHex(129, 17, 255, 0),  // Фиолетовый
+	'I': RGBAtoHex(255, 153, 255, 0), // Розовый
+	'C': RGBAtoHex(136, 221, 255, 0), // Голубой
+	'L': RGBAtoHex(119, 238, 0, 0),   // Салатовый
+	'E': RGBAtoHex(255, 255, 187, 0), // Кремовый
+	'X': RGBAtoHex(86, 86, 86, 0),    // Серый
+	'K': RGBAtoHex(0, 0, 0, 0),       // Чёрный
+	'T': "transparent",               // Прозрачный
+}
+
+// shadeColor затемняет цвет в формате #rrggbb, умножая каналы на k.
+// Цвета в другом формате (например, transparent) возвращаются без изменений
+func shadeColor(hex string, k float64) string {
+	var r, g, b int
+	if len(hex) != 7 {
+		return hex
+	}
+	if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return hex
+	}
+	shade := func(c int) int { return int(float64(c)*k + 0.5) }
+	return RGBAtoHex(shade(r), shade(g), shade(b), 0)
+}
+
+// Цвета сторон собранного FTO (октаэдра) по умолчанию: противоположные стороны
+// U–D, F–B, R–BL и L–BR. Серый (X) не используется: он обозначает скрытые наклейки
+var ftoSchemeColors = map[string]string{
+	"U":  "W", // Белый
+	"F":  "G", // Зеленый
+	"R":  "R", // Красный
+	"L":  "P", // Фиолетовый
+	"B":  "B", // Синий
+	"D":  "Y", // Желтый
+	"BR": "O", // Оранжевый
+	"BL": "I", // Розовый
 }
 
 // var colorMapRGBAPastel = map[rune]string{
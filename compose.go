@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Composition хранит сцену из нескольких картинок головоломок
+type Composition struct {
+	Images  []ComposedImage // Картинки в порядке раскладки
+	Columns int             // Число столбцов сетки
+	Gap     float64         // Расстояние между картинками
+	Rotate  float64         // Угол поворота картинки в градусах
+}
+
+// ComposedImage одна картинка сцены
+type ComposedImage struct {
+	Content string     // Содержимое SVG без корневого элемента
+	ViewBox [4]float64 // Рамка (viewBox) картинки
+	Caption string     // Подпись под картинкой
+}
+
+// Параметры сцены
+const (
+	maxComposedImages  = 64  // Наибольшее число картинок в сцене
+	defaultComposeGap  = 20  // Расстояние между картинками по умолчанию
+	composeCaptionSize = 0.1 // Высота шрифта подписи в долях высоты ячейки
+)
+
+var (
+	svgRootRegexp   = regexp.MustCompile(`^<svg[^>]*viewBox="([^"]*)"[^>]*>`)
+	svgIDRegexp     = regexp.MustCompile(`\bid="`)
+	svgURLRefRegexp = regexp.MustCompile(`url\(#`)
+)
+
+// ParseComposeParams парсит раскладку сцены: row — картинки в строку, column — в столбец,
+// grid — сеткой по columns столбцов (по умолчанию почти квадратной). gap — расстояние между картинками
+func ParseComposeParams(pLayout, pColumns, pGap string, count int) (Composition, error) {
+	if count == 0 {
+		return Composition{}, fmt.Errorf("no images: add one or more image parameters like image=cube/isometric/3x3x3")
+	}
+	if count > maxComposedImages {
+		return Composition{}, fmt.Errorf("too many images: at most %d are allowed", maxComposedImages)
+	}
+
+	composition := Composition{Gap: defaultComposeGap}
+	switch pLayout {
+	case "row":
+		composition.Columns = count
+	case "column":
+		composition.Columns = 1
+	case "grid":
+		composition.Columns = int(math.Ceil(math.Sqrt(float64(count))))
+		if pColumns != "" {
+			columns, err := strconv.Atoi(pColumns)
+			if err != nil || columns < 1 {
+				return Composition{}, fmt.Errorf("invalid columns %q, expected a positive integer", pColumns)
+			}
+			composition.Columns = min(columns, count)
+		}
+	default:
+		return Composition{}, fmt.Errorf("unknown layout %q, expected row, column or grid", pLayout)
+	}
+
+	if pGap != "" {
+		gap, err := strconv.ParseFloat(pGap, 64)
+		if err != nil || math.IsNaN(gap) || gap < 0 || gap > 1000 {
+			return Composition{}, fmt.Errorf("invalid gap %q, expected a number from 0 to 1000", pGap)
+		}
+		composition.Gap = gap
+	}
+
+	return composition, nil
+}
+
+// CheckComposeCaptions проверяет, что подписи запрошены только для SVG: подписи — текст,
+// а растровые картинки строятся без текста
+func CheckComposeCaptions(captions []string, format string) error {
+	if format == FormatSVG {
+		return nil
+	}
+	for _, caption := range captions {
+		if caption != "" {
+			return fmt.Errorf("captions are only supported in SVG images, remove the .png or .jpg extension or the captions")
+		}
+	}
+	return nil
+}
+
+// RenderComposedImage строит картинку сцены запросом к обработчику handler.
+// spec — путь картинки без /v1/ с параметрами, например cube/isometric/3x3x3?alg=R U.
+// Идентификаторы элементов получают префикс prefix, чтобы не совпадать у разных картинок
+func RenderComposedImage(handler http.Handler, spec, prefix string) (ComposedImage, error) {
+	spec = strings.TrimPrefix(strings.TrimPrefix(spec, "/"), "v1/")
+	target, err := url.Parse("/v1/" + spec)
+	if err != nil || spec == "" {
+		return ComposedImage{}, fmt.Errorf("invalid image %q", spec)
+	}
+	// Вложенную сцену ищем по разобранному пути: в spec адрес может быть закодирован (%63ompose)
+	if strings.HasPrefix(path.Clean(target.Path)+"/", "/v1/compose/") {
+		return ComposedImage{}, fmt.Errorf("invalid image %q: compositions can not be nested", spec)
+	}
+
+	// Картинка всегда запрашивается в SVG. Адрес подставляется уже разобранным:
+	// в параметрах картинки могут быть пробелы и апострофы, как в алгоритмах
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.URL = target
+	request.Header.Set("Accept", "image/svg+xml")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	body := recorder.Body.String()
+	if recorder.Code != http.StatusOK {
+		var response struct{ Error string }
+		if json.Unmarshal(recorder.Body.Bytes(), &response) != nil || response.Error == "" {
+			response.Error = http.StatusText(recorder.Code)
+		}
+		return ComposedImage{}, fmt.Errorf("image %q: %s", spec, response.Error)
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "image/svg+xml") {
+		return ComposedImage{}, fmt.Errorf("image %q: expected an SVG image, remove the .png or .jpg extension", spec)
+	}
+
+	// Отделяем содержимое от корневого элемента и читаем его рамку
+	root := svgRootRegexp.FindStringSubmatch(body)
+	if root == nil {
+		return ComposedImage{}, fmt.Errorf("image %q: missing viewBox", spec)
+	}
+	viewBox := parseNumbers(root[1])
+	if len(viewBox) != 4 {
+		return ComposedImage{}, fmt.Errorf("image %q: missing viewBox", spec)
+	}
+	content := strings.TrimSuffix(body[len(root[0]):], "\r\n</svg>")
+	content = svgIDRegexp.ReplaceAllString(content, `id="`+prefix)
+	content = svgURLRefRegexp.ReplaceAllString(content, `url(#`+prefix)
+
+	return ComposedImage{Content: content, ViewBox: [4]float64(viewBox)}, nil
+}
+
+// GenerateComposition генерирует SVG сцену: картинки раскладываются по ячейкам одного размера
+// (по самой большой картинке) и выравниваются по центру ячейки, подписи идут под картинками
+func GenerateComposition(composition Composition) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Размер ячейки и высота строки подписей
+	var cell Point
+	hasCaptions := false
+	for _, image := range composition.Images {
+		cell.X = math.Max(cell.X, image.ViewBox[2])
+		cell.Y = math.Max(cell.Y, image.ViewBox[3])
+		hasCaptions = hasCaptions || image.Caption != ""
+	}
+	fontSize := math.Round(cell.Y * composeCaptionSize)
+	captionHeight := 0.0
+	if hasCaptions {
+		captionHeight = fontSize * 1.6
+	}
+
+	// Размер рамки (viewBox)
+	columns := composition.Columns
+	rows := (len(composition.Images) + columns - 1) / columns
+	width := float64(columns)*cell.X + float64(columns-1)*composition.Gap
+	height := float64(rows)*(cell.Y+captionHeight) + float64(rows-1)*composition.Gap
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, width, height, composition.Rotate)
+
+	for i, image := range composition.Images {
+		// Левый верхний угол ячейки
+		x := float64(i%columns) * (cell.X + composition.Gap)
+		y := float64(i/columns) * (cell.Y + captionHeight + composition.Gap)
+
+		// Картинка в своей группе, сдвинутой в центр ячейки
+		builder.WriteString(fmt.Sprintf("\r\n<g id=\"image-%d\" transform=\"translate(%s %s)\">", i+1,
+			formatNumber(x+(cell.X-image.ViewBox[2])/2), formatNumber(y+(cell.Y-image.ViewBox[3])/2)))
+		builder.WriteString(fmt.Sprintf("\r\n<svg width=\"%s\" height=\"%s\" viewBox=\"%s %s %s %s\">",
+			formatNumber(image.ViewBox[2]), formatNumber(image.ViewBox[3]),
+			formatNumber(image.ViewBox[0]), formatNumber(image.ViewBox[1]), formatNumber(image.ViewBox[2]), formatNumber(image.ViewBox[3])))
+		builder.WriteString(image.Content)
+		builder.WriteString("\r\n</svg>")
+		builder.WriteString("\r\n</g>")
+
+		// Подпись под ячейкой
+		if image.Caption != "" {
+			builder.WriteString(fmt.Sprintf("\r\n<text id=\"caption-%d\" x=\"%s\" y=\"%s\" font-family=\"sans-serif\" font-size=\"%s\" text-anchor=\"middle\" style=\"fill: %s\">%s</text>",
+				i+1, formatNumber(x+cell.X/2), formatNumber(y+cell.Y+fontSize*1.2), formatNumber(fontSize), colorMapRGBA['K'], html.EscapeString(image.Caption)))
+		}
+	}
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, composition.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
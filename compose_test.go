@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// svgHandler отвечает на любой запрос одной и той же SVG картинкой и запоминает путь запроса
+type svgHandler struct {
+	paths []string
+}
+
+func (h *svgHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.paths = append(h.paths, r.URL.Path)
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 100 50\">\r\n\t<path id=\"base\" style=\"fill: url(#grad)\"/>\r\n</svg>"))
+}
+
+func TestRenderComposedImage(t *testing.T) {
+	handler := &svgHandler{}
+	image, err := RenderComposedImage(handler, "/v1/cube/isometric/3x3x3?alg=R U", "image-1-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := handler.paths, []string{"/v1/cube/isometric/3x3x3"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("requested paths = %v, want %v", got, want)
+	}
+	if image.ViewBox != [4]float64{0, 0, 100, 50} {
+		t.Errorf("viewBox = %v, want [0 0 100 50]", image.ViewBox)
+	}
+	if want := "\r\n\t<path id=\"image-1-base\" style=\"fill: url(#image-1-grad)\"/>"; image.Content != want {
+		t.Errorf("content = %q, want %q", image.Content, want)
+	}
+}
+
+func TestRenderComposedImageRejectsNesting(t *testing.T) {
+	for _, spec := range []string{
+		"compose/row?image=cube/isometric/3x3x3",
+		"/v1/compose/row",
+		"%63ompose/row",
+		"%63%6fmpose/row",
+		"cube/../compose/row",
+		"./compose/row",
+		"compose",
+	} {
+		handler := &svgHandler{}
+		_, err := RenderComposedImage(handler, spec, "image-1-")
+		if err == nil || !strings.Contains(err.Error(), "nested") {
+			t.Errorf("RenderComposedImage(%q) error = %v, want a nesting error", spec, err)
+		}
+		if len(handler.paths) != 0 {
+			t.Errorf("RenderComposedImage(%q) requested %v", spec, handler.paths)
+		}
+	}
+}
+
+func TestCheckComposeCaptions(t *testing.T) {
+	tests := []struct {
+		captions []string
+		format   string
+		ok       bool
+	}{
+		{[]string{"Before", "After"}, FormatSVG, true},
+		{nil, FormatPNG, true},
+		{[]string{"", ""}, FormatJPEG, true},
+		{[]string{"Before"}, FormatPNG, false},
+		{[]string{"", "After"}, FormatJPEG, false},
+	}
+
+	for _, tt := range tests {
+		if err := CheckComposeCaptions(tt.captions, tt.format); (err == nil) != tt.ok {
+			t.Errorf("CheckComposeCaptions(%q, %s) error = %v, want ok = %v", tt.captions, tt.format, err, tt.ok)
+		}
+	}
+}
+
+func TestParseComposeParams(t *testing.T) {
+	tests := []struct {
+		layout, columns, gap string
+		count                int
+		wantColumns          int
+		wantGap              float64
+	}{
+		{"row", "", "", 3, 3, defaultComposeGap},
+		{"column", "", "0", 3, 1, 0},
+		{"grid", "", "", 5, 3, defaultComposeGap},
+		{"grid", "2", "7.5", 5, 2, 7.5},
+		{"grid", "9", "", 5, 5, defaultComposeGap},
+	}
+
+	for _, tt := range tests {
+		composition, err := ParseComposeParams(tt.layout, tt.columns, tt.gap, tt.count)
+		if err != nil {
+			t.Errorf("ParseComposeParams(%q, %q, %q, %d): %v", tt.layout, tt.columns, tt.gap, tt.count, err)
+			continue
+		}
+		if composition.Columns != tt.wantColumns || composition.Gap != tt.wantGap {
+			t.Errorf("ParseComposeParams(%q, %q, %q, %d) = %d columns, gap %v, want %d, %v",
+				tt.layout, tt.columns, tt.gap, tt.count, composition.Columns, composition.Gap, tt.wantColumns, tt.wantGap)
+		}
+	}
+
+	for _, bad := range [][3]string{{"diagonal", "", ""}, {"grid", "0", ""}, {"grid", "x", ""}, {"row", "", "-1"}, {"row", "", "NaN"}} {
+		if _, err := ParseComposeParams(bad[0], bad[1], bad[2], 2); err == nil {
+			t.Errorf("ParseComposeParams(%q, %q, %q) returned no error", bad[0], bad[1], bad[2])
+		}
+	}
+	if _, err := ParseComposeParams("row", "", "", 0); err == nil {
+		t.Error("ParseComposeParams accepted no images")
+	}
+	if _, err := ParseComposeParams("row", "", "", maxComposedImages+1); err == nil {
+		t.Error("ParseComposeParams accepted too many images")
+	}
+}
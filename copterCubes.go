@@ -0,0 +1,55 @@
+package main
+
+import "math"
+
+// Параметры дуг кёрви коптера (в долях стороны): дуга угла — окружность с центром,
+// сдвинутым от угла наружу по диагонали, которая пересекает соседние рёбра за их серединами
+const (
+	curvyCopterShift = 0.25 // Сдвиг центра дуги от угла по каждой из осей
+	curvyCopterReach = 0.58 // Расстояние от угла до точки, где дуга пересекает ребро
+)
+
+// helicopterCells делит сторону хеликоптера на четыре угла (треугольники, отрезанные
+// прямыми между серединами рёбер) и четыре центра, которые сходятся в центре стороны
+func helicopterCells() [][]Point {
+	corner := []Point{unitTopLeft, {X: 0.5, Y: 0}, {X: 0, Y: 0.5}}
+	center := []Point{{X: 0.5, Y: 0}, {X: 0.5, Y: 0.5}, {X: 0, Y: 0.5}}
+	return append(rotateQuarters(corner), rotateQuarters(center)...)
+}
+
+// curvyCopterCells делит сторону кёрви коптера дугами углов: четыре угла, четыре центра
+// и четыре ребра — треугольники у середин рёбер, где пересекаются дуги соседних углов
+func curvyCopterCells() [][]Point {
+	s, e := curvyCopterShift, curvyCopterReach
+	r := math.Hypot(e+s, s)
+
+	// Центры дуг левого верхнего угла и его соседей справа и снизу
+	arcTopLeft := Point{X: -s, Y: -s}
+	arcTopRight := Point{X: 1 + s, Y: -s}
+	arcBottomLeft := Point{X: -s, Y: 1 + s}
+
+	// Пересечения дуги левого верхнего угла с дугами соседей: у верхнего и у левого ребра
+	h := math.Sqrt(r*r-(0.5+s)*(0.5+s)) - s
+	top, left := Point{X: 0.5, Y: h}, Point{X: h, Y: 0.5}
+
+	corner := curvedPolygon(unitTopLeft,
+		lineTo(Point{X: 1 - e, Y: 0}),
+		arcTo(arcTopRight, top),
+		arcTo(arcTopLeft, left),
+		arcTo(arcBottomLeft, Point{X: 0, Y: 1 - e}),
+		lineTo(unitTopLeft),
+	)
+	edge := curvedPolygon(Point{X: 1 - e, Y: 0},
+		lineTo(Point{X: e, Y: 0}),
+		arcTo(arcTopLeft, top),
+		arcTo(arcTopRight, Point{X: 1 - e, Y: 0}),
+	)
+	center := curvedPolygon(top,
+		lineTo(Point{X: 0.5, Y: 0.5}),
+		lineTo(left),
+		arcTo(arcTopLeft, top),
+	)
+
+	cells := append(rotateQuarters(corner), rotateQuarters(edge)...)
+	return append(cells, rotateQuarters(center)...)
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// CornerCube хранит параметры картинки головоломки в форме кубика, у которой вращаются
+// углы (дино, рекс, реди, айви) или рёбра (хеликоптер, кёрви коптер)
+type CornerCube struct {
+	Puzzle string          // Головоломка: dino, rex, redi, ivy, helicopter или curvycopter
+	Colors map[Side][]rune // Карта для хранения цветов каждой стороны
+	Rotate float64         // Угол поворота картинки в градусах
+}
+
+// Головоломки, которые рисуются на контуре кубика
+var cornerCubePuzzles = []string{"dino", "rex", "redi", "ivy", "helicopter", "curvycopter"}
+
+// Размер кубика, контур которого используется как основа
+var cornerCubeSize = Size{X: 3, Y: 3, Z: 3}
+
+// Параметры построения наклеек (в точках)
+const (
+	cornerCubeGap   = 3.5 // Отступ наклейки от границ детали
+	cornerCubeRound = 6   // Радиус скругления углов наклейки
+	cornerCubeArc   = 16  // Число отрезков, которыми рисуется дуга
+)
+
+// Параметры разрезов на стороне (в долях стороны)
+const (
+	rexRadius  = 0.8  // Радиус дуг рекса с центрами в углах стороны
+	rediCorner = 0.36 // Сторона квадрата угла реди
+)
+
+// Углы единичного квадрата стороны
+var (
+	unitTopLeft     = Point{X: 0, Y: 0}
+	unitTopRight    = Point{X: 1, Y: 0}
+	unitBottomLeft  = Point{X: 0, Y: 1}
+	unitBottomRight = Point{X: 1, Y: 1}
+)
+
+// Углы айви, которые вращаются, на сторонах (в рамке стороны, как у развёртки кубика;
+// изометрический вид использует те же рамки). Это углы UFR, UBL, DFL и DRB
+var ivyTurningCorners = map[Side][2]Point{
+	Front: {unitTopRight, unitBottomLeft},
+	Left:  {unitTopLeft, unitBottomRight},
+	Up:    {unitTopLeft, unitBottomRight},
+	Right: {unitTopLeft, unitBottomRight},
+	Down:  {unitTopLeft, unitBottomRight},
+	Back:  {unitTopRight, unitBottomLeft},
+}
+
+// ParseCornerCubeParams парсит цвета видимых сторон: {front}-{up}-{right}-{base}
+func ParseCornerCubeParams(puzzle, pColors string) (CornerCube, error) {
+	return parseCornerCubeColors(puzzle, []Side{Front, Up, Right}, pColors)
+}
+
+// ParseUnfoldedCornerCubeParams парсит цвета всех сторон для развёртки:
+// {front}-{left}-{up}-{right}-{down}-{back}-{base}
+func ParseUnfoldedCornerCubeParams(puzzle, pColors string) (CornerCube, error) {
+	return parseCornerCubeColors(puzzle, stateSides[:], pColors)
+}
+
+// parseCornerCubeColors парсит цвета сторон sides, за которыми идёт цвет основы
+func parseCornerCubeColors(puzzle string, sides []Side, pColors string) (CornerCube, error) {
+	known := false
+	for _, p := range cornerCubePuzzles {
+		known = known || p == puzzle
+	}
+	if !known {
+		return CornerCube{}, fmt.Errorf("unknown puzzle: %s", puzzle)
+	}
+
+	// Инициализация структуры CornerCube
+	cube := CornerCube{
+		Puzzle: puzzle,
+		Colors: make(map[Side][]rune),
+	}
+
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrEmpty := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	// Парсинг цветов для каждой стороны
+	for i, side := range sides {
+		count := len(cornerCubeCells(puzzle, side, 0))
+		cube.Colors[side] = stringToRuneGrid(getColorOrEmpty(i, "X"), count, 1)[0]
+	}
+
+	// Цвет фона (base) будет последним в массиве Colors
+	cube.Colors[Base] = stringToRuneGrid(getColorOrEmpty(len(sides), "K"), 1, 1)[0]
+
+	return cube, nil
+}
+
+// GenerateIsometricCornerCube генерирует изометрическую SVG картинку головоломки
+// на контуре кубика 3x3x3
+func GenerateIsometricCornerCube(cube CornerCube) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Рамка и основа — как у изометрического кубика
+	viewBoxSize, basePath := isometricCubeBase(cornerCubeSize)
+	faces := isometricCubeFaces(cornerCubeSize)
+
+	// Отступ наклеек: у айви — в долях стороны (поперёк стороны ромба она короче в sin 60° раз)
+	gap, unitGap := cornerCubeGap, 0.0
+	if cube.Puzzle == "ivy" {
+		axis := faces[Front].AxisX
+		gap, unitGap = 0, cornerCubeGap/(math.Hypot(axis.X, axis.Y)*math.Sqrt(3)/2)
+	}
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, cube.Rotate)
+
+	// Создаём основу (base)
+	colorBase := cube.Colors[Base][0]
+	builder.WriteString(fmt.Sprintf("\r\n\t<path id=\"base\" d=\"%s\" style=\"fill: %s\"/>", basePath, colorMapRGBA[colorBase]))
+
+	// Создаём стороны (side)
+	for _, side := range []Side{Front, Up, Right} {
+		face := faces[side]
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", side.String()))
+		for i, cell := range cornerCubeCells(cube.Puzzle, side, unitGap) {
+			polygon := make([]Point, len(cell))
+			for j, p := range cell {
+				polygon[j] = face.point(p)
+			}
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%c-%d\" d=\"%s\" style=\"fill: %s\"/>",
+				side.String()[0], i+1, roundedStickerPath(polygon, gap, cornerCubeRound), colorMapRGBA[cube.Colors[side][i]]))
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, cube.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// GenerateUnfoldedCornerCube генерирует развёртку SVG картинку головоломки на контуре
+// развёртки кубика 3x3x3
+func GenerateUnfoldedCornerCube(cube CornerCube) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Стороны раскладываются, как у развёртки кубика 3x3x3: наклейки занимают квадрат
+	// из трёх клеток по 49 точек с отступом 4 точки от края основы
+	size := float64(3 * 49)
+	origins, viewBoxSize, outline := unfoldedCubeLayout(8 + size)
+
+	// Отступ наклеек айви — в долях стороны
+	gap, unitGap := cornerCubeGap, 0.0
+	if cube.Puzzle == "ivy" {
+		gap, unitGap = 0, cornerCubeGap/size
+	}
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, cube.Rotate)
+
+	// Создаём основу (base)
+	colorBase := cube.Colors[Base][0]
+	builder.WriteString(fmt.Sprintf("\r\n\t<path id=\"base\" d=\"%s\" style=\"fill: %s\"/>",
+		roundedPolygonPath(outline, skewbBase), colorMapRGBA[colorBase]))
+
+	// Создаём стороны (side)
+	for _, side := range stateSides {
+		origin := origins[side]
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", side.String()))
+		for i, cell := range cornerCubeCells(cube.Puzzle, side, unitGap) {
+			polygon := make([]Point, len(cell))
+			for j, p := range cell {
+				polygon[j] = Point{X: origin.X + 4 + p.X*size, Y: origin.Y + 4 + p.Y*size}
+			}
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%c-%d\" d=\"%s\" style=\"fill: %s\"/>",
+				side.String()[0], i+1, roundedStickerPath(polygon, gap, cornerCubeRound), colorMapRGBA[cube.Colors[side][i]]))
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, cube.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// cornerCubeCells возвращает наклейки стороны side в единичном квадрате (X вправо, Y вниз)
+// построчно: сверху вниз и слева направо по их центрам. Отступ gap (в долях стороны)
+// нужен только айви, остальные наклейки сжимаются при построении
+func cornerCubeCells(puzzle string, side Side, gap float64) [][]Point {
+	var cells [][]Point
+	rows := 4.0
+	switch puzzle {
+	case "dino":
+		cells = dinoCells()
+	case "rex":
+		cells = rexCells()
+	case "redi":
+		cells = rediCells()
+	case "ivy":
+		cells = ivyCells(ivyTurningCorners[side], gap)
+	case "helicopter":
+		cells = helicopterCells()
+	case "curvycopter":
+		// Центры кёрви коптера лежат у середины стороны, между рядами углов и боковых рёбер
+		cells, rows = curvyCopterCells(), 8
+	}
+
+	// Центры наклеек одного ряда (например, угла и ребра) лежат на разной высоте,
+	// поэтому ряды считаются по четвертям стороны (у кёрви коптера — по восьмым)
+	sort.SliceStable(cells, func(i, j int) bool {
+		a, b := polygonCentroid(cells[i]), polygonCentroid(cells[j])
+		if rowA, rowB := math.Round(a.Y*rows), math.Round(b.Y*rows); rowA != rowB {
+			return rowA < rowB
+		}
+		return a.X < b.X
+	})
+	return cells
+}
+
+// rotateQuarters возвращает многоугольник и три его копии, повёрнутые на четверть оборота
+// вокруг центра стороны
+func rotateQuarters(points []Point) [][]Point {
+	result := [][]Point{points}
+	for i := 1; i < 4; i++ {
+		prev := result[i-1]
+		next := make([]Point, len(prev))
+		for j, p := range prev {
+			next[j] = Point{X: 1 - p.Y, Y: p.X}
+		}
+		result = append(result, next)
+	}
+	return result
+}
+
+// arcPoints возвращает точки короткой дуги окружности с центром center от точки from
+// до точки to (без начальной точки)
+func arcPoints(center, from, to Point) []Point {
+	radius := math.Hypot(from.X-center.X, from.Y-center.Y)
+	start := math.Atan2(from.Y-center.Y, from.X-center.X)
+	sweep := math.Atan2(to.Y-center.Y, to.X-center.X) - start
+	for sweep > math.Pi {
+		sweep -= 2 * math.Pi
+	}
+	for sweep < -math.Pi {
+		sweep += 2 * math.Pi
+	}
+
+	points := make([]Point, 0, cornerCubeArc)
+	for i := 1; i <= cornerCubeArc; i++ {
+		angle := start + sweep*float64(i)/cornerCubeArc
+		points = append(points, Point{X: center.X + radius*math.Cos(angle), Y: center.Y + radius*math.Sin(angle)})
+	}
+	return points
+}
+
+// curveStep — участок границы детали до точки To: отрезок или короткая дуга окружности с центром Center
+type curveStep struct {
+	To     Point
+	Center Point
+	Arc    bool
+}
+
+// lineTo и arcTo задают участки границы для curvedPolygon
+func lineTo(to Point) curveStep        { return curveStep{To: to} }
+func arcTo(center, to Point) curveStep { return curveStep{To: to, Center: center, Arc: true} }
+
+// curvedPolygon строит многоугольник из начальной точки и участков границы.
+// Последний участок должен возвращаться в начальную точку
+func curvedPolygon(start Point, steps ...curveStep) []Point {
+	points := []Point{start}
+	for _, step := range steps {
+		if step.Arc {
+			points = append(points, arcPoints(step.Center, points[len(points)-1], step.To)...)
+		} else {
+			points = append(points, step.To)
+		}
+	}
+	return points[:len(points)-1]
+}
+
+// dinoCells делит сторону дино диагоналями на четыре треугольника — наклейки рёбер
+func dinoCells() [][]Point {
+	return rotateQuarters([]Point{unitTopLeft, unitTopRight, {X: 0.5, Y: 0.5}})
+}
+
+// rediCells делит сторону реди на четыре угла (квадраты со срезанным внутренним углом)
+// и четыре ребра, которые сходятся в центре стороны
+func rediCells() [][]Point {
+	t := rediCorner
+	corner := []Point{unitTopLeft, {X: t, Y: 0}, {X: t, Y: t / 2}, {X: t / 2, Y: t}, {X: 0, Y: t}}
+	edge := []Point{
+		{X: t, Y: 0}, {X: 1 - t, Y: 0}, {X: 1 - t, Y: t / 2}, {X: 1 - 0.75*t, Y: 0.75 * t},
+		{X: 0.5, Y: 0.5}, {X: 0.75 * t, Y: 0.75 * t}, {X: t, Y: t / 2},
+	}
+	return append(rotateQuarters(corner), rotateQuarters(edge)...)
+}
+
+// rexCells делит сторону рекса дугами с центрами в углах стороны: четыре угла,
+// четыре ребра, четыре лепестка и центр
+func rexCells() [][]Point {
+	r := rexRadius
+	q := (1 - math.Sqrt(2*r*r-1)) / 2 // Пересечение дуг соседних углов на диагонали
+	h := 1 - math.Sqrt(r*r-0.25)      // Вершина центра у середины ребра
+
+	// Вершины центра у середин рёбер: сверху, справа, снизу и слева
+	top, right, bottom, left := Point{X: 0.5, Y: h}, Point{X: 1 - h, Y: 0.5}, Point{X: 0.5, Y: 1 - h}, Point{X: h, Y: 0.5}
+
+	corner := curvedPolygon(unitTopLeft,
+		lineTo(Point{X: 1 - r, Y: 0}),
+		arcTo(unitTopRight, Point{X: q, Y: q}),
+		arcTo(unitBottomLeft, Point{X: 0, Y: 1 - r}),
+		lineTo(unitTopLeft),
+	)
+	edge := curvedPolygon(Point{X: 1 - r, Y: 0},
+		lineTo(Point{X: r, Y: 0}),
+		arcTo(unitTopLeft, Point{X: 1 - q, Y: q}),
+		arcTo(unitBottomRight, top),
+		arcTo(unitBottomLeft, Point{X: q, Y: q}),
+		arcTo(unitTopRight, Point{X: 1 - r, Y: 0}),
+	)
+	petal := curvedPolygon(Point{X: q, Y: q},
+		arcTo(unitBottomLeft, top),
+		arcTo(unitBottomRight, left),
+		arcTo(unitTopRight, Point{X: q, Y: q}),
+	)
+	center := curvedPolygon(top,
+		arcTo(unitBottomLeft, right),
+		arcTo(unitTopLeft, bottom),
+		arcTo(unitTopRight, left),
+		arcTo(unitBottomRight, top),
+	)
+
+	cells := append(rotateQuarters(corner), rotateQuarters(edge)...)
+	cells = append(cells, rotateQuarters(petal)...)
+	return append(cells, center)
+}
+
+// ivyCells делит сторону айви на два угла, которые вращаются, и лепесток между ними:
+// лепесток — пересечение кругов радиуса стороны с центрами в этих углах. Детали айви
+// сходятся в острия, поэтому наклейки строятся сразу с отступом gap (в долях стороны):
+// круги лепестка уменьшаются на gap, а углы отступают от сторон и от увеличенного круга
+func ivyCells(turning [2]Point, gap float64) [][]Point {
+	a, b := turning[0], turning[1]
+	center := Point{X: 0.5, Y: 0.5}
+
+	// Две другие вершины квадрата — концы лепестка
+	ends := []Point{}
+	for _, p := range []Point{unitTopLeft, unitTopRight, unitBottomLeft, unitBottomRight} {
+		if p != a && p != b {
+			ends = append(ends, p)
+		}
+	}
+	m, n := ends[0], ends[1]
+
+	// Концы лепестка — пересечения уменьшенных кругов на диагонали mn
+	t := math.Sqrt((1-gap)*(1-gap)-0.5) / math.Sqrt(0.5)
+	tipM := Point{X: center.X + (m.X-center.X)*t, Y: center.Y + (m.Y-center.Y)*t}
+	tipN := Point{X: center.X + (n.X-center.X)*t, Y: center.Y + (n.Y-center.Y)*t}
+	petal := curvedPolygon(tipM, arcTo(a, tipN), arcTo(b, tipM))
+
+	// corner строит угол c, который лежит снаружи круга с центром в противоположном угле o
+	corner := func(c, o Point) []Point {
+		inner := Point{X: c.X + (center.X-c.X)*2*gap, Y: c.Y + (center.Y-c.Y)*2*gap}
+
+		// Точка на стороне от inner к концу end, лежащая на увеличенном круге
+		onCircle := func(end Point) Point {
+			d := Point{X: end.X - c.X, Y: end.Y - c.Y}
+			f := Point{X: inner.X - o.X, Y: inner.Y - o.Y}
+			r := 1 + gap
+			bq := 2 * (f.X*d.X + f.Y*d.Y)
+			cq := f.X*f.X + f.Y*f.Y - r*r
+			s := (-bq - math.Sqrt(bq*bq-4*cq)) / 2
+			return Point{X: inner.X + d.X*s, Y: inner.Y + d.Y*s}
+		}
+		return curvedPolygon(inner, lineTo(onCircle(m)), arcTo(o, onCircle(n)), lineTo(inner))
+	}
+	return [][]Point{corner(a, b), petal, corner(b, a)}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var bandageGroupRegexp = regexp.MustCompile(`^([UDFBLR]\d+){2,}$`)
+
+// ParseBandage парсит склейки кубика вида U6U7F0,R2R5. Каждая группа — элементы развёртки
+// (буква стороны и номер элемента слева направо, сверху вниз, начиная с 0), детали которых
+// склеены в один блок
+func ParseBandage(pBandage string) ([][]StickerRef, error) {
+	var groups [][]StickerRef
+	for _, pGroup := range strings.Split(pBandage, ",") {
+		if !bandageGroupRegexp.MatchString(pGroup) {
+			return nil, fmt.Errorf("invalid bandage group %q: expected 2 or more stickers like U6U7F0", pGroup)
+		}
+		var group []StickerRef
+		for _, ref := range stickerRefRegexp.FindAllStringSubmatch(pGroup, -1) {
+			index, err := strconv.Atoi(ref[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid bandage sticker %q: %w", ref[0], err)
+			}
+			group = append(group, StickerRef{Face: rune(ref[1][0]), Index: index})
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// Bandage склеивает детали кубика в блоки. Блок — все детали, на которых лежат элементы группы;
+// детали блока должны быть соседними, а блоки не должны пересекаться. Номер блока
+// записывается во все наклейки его деталей и дальше движется вместе с ними
+func (s *CubeState) Bandage(groups [][]StickerRef) error {
+	// Стороны по буквам в записи элементов
+	sides := make(map[rune]Side)
+	for _, side := range stateSides {
+		sides[[]rune(strings.ToUpper(side.String()))[0]] = side
+	}
+
+	for i, group := range groups {
+		block := i + 1
+
+		// Детали блока по его элементам
+		pieces := make(map[Vec3]bool)
+		for _, ref := range group {
+			side := sides[ref.Face]
+			w, h := s.Size.faceDims(side)
+			if ref.Index >= w*h {
+				return fmt.Errorf("invalid bandage sticker %c%d: the face has %d stickers", ref.Face, ref.Index, w*h)
+			}
+			pieces[s.stickerPos(side, ref.Index/w, ref.Index%w)] = true
+		}
+
+		// В блоке не меньше двух деталей, и они касаются друг друга гранями
+		if len(pieces) < 2 {
+			return fmt.Errorf("invalid bandage group %d: the stickers belong to one piece", block)
+		}
+		if !piecesConnected(pieces) {
+			return fmt.Errorf("invalid bandage group %d: the pieces are not adjacent", block)
+		}
+
+		for j := range s.Stickers {
+			st := &s.Stickers[j]
+			if !pieces[st.Pos] {
+				continue
+			}
+			if st.Block != 0 {
+				return fmt.Errorf("invalid bandage group %d: it overlaps group %d", block, st.Block)
+			}
+			st.Block = block
+		}
+	}
+	return nil
+}
+
+// piecesConnected проверяет, что детали связаны соседством по граням
+func piecesConnected(pieces map[Vec3]bool) bool {
+	var start Vec3
+	for p := range pieces {
+		start = p
+		break
+	}
+
+	visited := map[Vec3]bool{start: true}
+	queue := []Vec3{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, n := range sideNormals {
+			// Координаты удвоены, поэтому соседняя деталь отстоит на 2
+			q := Vec3{X: p.X + 2*n.X, Y: p.Y + 2*n.Y, Z: p.Z + 2*n.Z}
+			if pieces[q] && !visited[q] {
+				visited[q] = true
+				queue = append(queue, q)
+			}
+		}
+	}
+	return len(visited) == len(pieces)
+}
+
+// checkBandage возвращает ошибку, если ход разрезает склеенный блок:
+// часть его деталей поворачивается, а часть остаётся на месте
+func (s *CubeState) checkBandage(move Move) error {
+	turned := make(map[int]bool)
+	kept := make(map[int]bool)
+	for _, st := range s.Stickers {
+		if st.Block == 0 {
+			continue
+		}
+		if c := st.Pos.coord(move.Axis); c < move.Min || c > move.Max {
+			kept[st.Block] = true
+		} else {
+			turned[st.Block] = true
+		}
+		if turned[st.Block] && kept[st.Block] {
+			return fmt.Errorf("illegal move %s: it splits bandaged block %d", move.Name, st.Block)
+		}
+	}
+	return nil
+}
+
+// bandaged сообщает, есть ли у кубика склеенные блоки
+func (s *CubeState) bandaged() bool {
+	for _, st := range s.Stickers {
+		if st.Block != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// blockState возвращает копию состояния, в которой вместо цвета наклейки записан номер её блока
+// (0 — деталь не склеена). Так номера блоков раскладываются по видам теми же функциями, что и цвета
+func (s *CubeState) blockState() *CubeState {
+	blocks := &CubeState{Size: s.Size, Stickers: append([]Sticker(nil), s.Stickers...)}
+	for i := range blocks.Stickers {
+		blocks.Stickers[i].Color = rune(blocks.Stickers[i].Block)
+		blocks.Stickers[i].Block = 0
+	}
+	return blocks
+}
+
+// stickerGroups находит на стороне элементы, которые рисуются одной наклейкой: соседние элементы
+// одного блока и одного цвета. Сетки colors и blocks одинаковой формы; элемент задаётся парой
+// индексов сетки. Возвращает группы из двух и более элементов и отметки, какие элементы в них вошли
+func stickerGroups(colors, blocks [][]rune) ([][][2]int, map[[2]int]bool) {
+	if blocks == nil {
+		return nil, nil
+	}
+
+	var groups [][][2]int
+	merged := make(map[[2]int]bool)
+	visited := make(map[[2]int]bool)
+	for i := range blocks {
+		for j := range blocks[i] {
+			if blocks[i][j] == 0 || visited[[2]int{i, j}] {
+				continue
+			}
+
+			// Обходим соседей того же блока и цвета
+			group := [][2]int{{i, j}}
+			visited[[2]int{i, j}] = true
+			for k := 0; k < len(group); k++ {
+				a, b := group[k][0], group[k][1]
+				for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					p, q := a+d[0], b+d[1]
+					if p < 0 || p >= len(blocks) || q < 0 || q >= len(blocks[p]) || visited[[2]int{p, q}] {
+						continue
+					}
+					if blocks[p][q] == blocks[i][j] && colors[p][q] == colors[i][j] {
+						visited[[2]int{p, q}] = true
+						group = append(group, [2]int{p, q})
+					}
+				}
+			}
+
+			if len(group) > 1 {
+				groups = append(groups, group)
+				for _, cell := range group {
+					merged[cell] = true
+				}
+			}
+		}
+	}
+	return groups, merged
+}
+
+// gridOutlines возвращает контуры объединения клеток сетки: X — второй индекс клетки,
+// Y — первый, вершины лежат на линиях сетки. Внешние контуры имеют положительную площадь,
+// контуры дыр — отрицательную
+func gridOutlines(cells [][2]int) [][]Point {
+	type edge struct{ from, to [2]int }
+
+	// Стороны клеток по часовой стрелке (Y вниз); общие стороны соседних клеток сокращаются
+	edges := make(map[edge]bool)
+	for _, cell := range cells {
+		y, x := cell[0], cell[1]
+		corners := [][2]int{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}}
+		for k := range corners {
+			e := edge{corners[k], corners[(k+1)%4]}
+			if reverse := (edge{e.to, e.from}); edges[reverse] {
+				delete(edges, reverse)
+			} else {
+				edges[e] = true
+			}
+		}
+	}
+
+	// Собираем оставшиеся стороны в замкнутые контуры
+	next := make(map[[2]int][]edge)
+	for e := range edges {
+		next[e.from] = append(next[e.from], e)
+	}
+
+	// В вершине, где клетки касаются углами, выходят две стороны: выбор не должен зависеть от обхода карты
+	for _, list := range next {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].to[1] != list[j].to[1] {
+				return list[i].to[1] < list[j].to[1]
+			}
+			return list[i].to[0] < list[j].to[0]
+		})
+	}
+	var outlines [][]Point
+	for len(edges) > 0 {
+		// Начинаем с самой верхней левой стороны, чтобы порядок контуров не зависел от обхода карты
+		var start edge
+		found := false
+		for e := range edges {
+			if !found || e.from[1] < start.from[1] || (e.from[1] == start.from[1] && e.from[0] < start.from[0]) ||
+				(e.from == start.from && (e.to[1] < start.to[1] || (e.to[1] == start.to[1] && e.to[0] < start.to[0]))) {
+				start, found = e, true
+			}
+		}
+
+		var outline []Point
+		for e := start; ; {
+			delete(edges, e)
+			outline = append(outline, Point{X: float64(e.from[0]), Y: float64(e.from[1])})
+			if e.to == start.from {
+				break
+			}
+			for _, n := range next[e.to] {
+				if edges[n] {
+					e = n
+					break
+				}
+			}
+		}
+		outlines = append(outlines, outline)
+	}
+	return outlines
+}
+
+// mergedStickerPath строит атрибут d наклейки из нескольких клеток: контуры объединения клеток
+// переводятся на картинку функцией point, сжимаются на gap и скругляются радиусом radius
+func mergedStickerPath(cells [][2]int, point func(Point) Point, gap, radius float64) string {
+	var d strings.Builder
+	for _, outline := range gridOutlines(cells) {
+		// Дыры расширяются, чтобы наклейка сжималась и вокруг них
+		inset := gap
+		if polygonArea(outline) < 0 {
+			inset = -gap
+		}
+		points := make([]Point, len(outline))
+		for i, p := range outline {
+			points[i] = point(p)
+		}
+		d.WriteString(roundedPolygonPath(insetPolygon(points, inset), radius))
+	}
+	return d.String()
+}
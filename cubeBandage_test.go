@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBandage(t *testing.T) {
+	groups, err := ParseBandage("U6U7F0,R2R5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]StickerRef{
+		{{Face: 'U', Index: 6}, {Face: 'U', Index: 7}, {Face: 'F', Index: 0}},
+		{{Face: 'R', Index: 2}, {Face: 'R', Index: 5}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("ParseBandage = %v, want %v", groups, want)
+	}
+}
+
+func TestParseBandageRejectsBadInput(t *testing.T) {
+	for _, pBandage := range []string{"", "U6", "U6,", "U6X7", "u6u7", "U6U99999999999999999999"} {
+		if _, err := ParseBandage(pBandage); err == nil {
+			t.Errorf("ParseBandage(%q) returned no error", pBandage)
+		}
+	}
+}
+
+func TestBandage(t *testing.T) {
+	tests := []struct {
+		bandage string
+		alg     string
+		ok      bool
+	}{
+		// Блок 1x1x2 из ребра UR и угла UFR: ходы R и U его не разрезают, а F разрезает
+		{"U5U8", "U R U'", true},
+		{"U5U8", "L D B", true},
+		{"U5U8", "F", false},
+		// После R блок стоит на ребре BR и угле UBR, и его разрезает уже U
+		{"U5U8", "R U", false},
+		{"U5U8", "R' F", true},
+		// Блок 1x2x3 из двух верхних рядов правой стороны
+		{"R0R1R2R3R4R5", "R L' R'", true},
+		{"R0R1R2R3R4R5", "U", false},
+		{"R0R1R2R3R4R5", "R2 U", true},
+		{"R0R1R2R3R4R5", "R2 D", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bandage+" "+tt.alg, func(t *testing.T) {
+			state := solvedState(t, "3x3x3")
+			groups, err := ParseBandage(tt.bandage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := state.Bandage(groups); err != nil {
+				t.Fatalf("Bandage: %v", err)
+			}
+			if err := state.ApplyAlgorithm(tt.alg); (err == nil) != tt.ok {
+				t.Errorf("ApplyAlgorithm(%q) error = %v, want ok = %v", tt.alg, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestBandageRejectsBadGroups(t *testing.T) {
+	for _, pBandage := range []string{
+		"U4U4",      // Одна деталь
+		"U0U8",      // Детали не касаются
+		"U0U9",      // Нет такого элемента
+		"U0U1,U1U2", // Блоки пересекаются
+	} {
+		state := solvedState(t, "3x3x3")
+		groups, err := ParseBandage(pBandage)
+		if err != nil {
+			t.Fatalf("ParseBandage(%q): %v", pBandage, err)
+		}
+		if err := state.Bandage(groups); err == nil {
+			t.Errorf("Bandage(%q) returned no error", pBandage)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Направления сторон по буквам нотации
+var faceLetterNormals = map[rune]Vec3{
+	'U': sideNormals[Up],
+	'D': sideNormals[Down],
+	'F': sideNormals[Front],
+	'B': sideNormals[Back],
+	'R': sideNormals[Right],
+	'L': sideNormals[Left],
+}
+
+// ViewFromCorner возвращает копию состояния, повёрнутую так, чтобы угол corner (например, DBL)
+// оказался на месте UFR: первая буква — сторона, которая будет сверху.
+// У кубоидов при повороте на четверть оборота размеры меняются местами
+func (s *CubeState) ViewFromCorner(corner string) (*CubeState, error) {
+	corner = strings.ToUpper(corner)
+	letters := []rune(corner)
+	if len(letters) != 3 {
+		return nil, fmt.Errorf("invalid corner %q: expected three faces like UFR", corner)
+	}
+
+	// Проверяем, что стороны угла лежат на разных осях
+	var normals [3]Vec3
+	sum := Vec3{}
+	for i, letter := range letters {
+		n, ok := faceLetterNormals[letter]
+		if !ok {
+			return nil, fmt.Errorf("invalid corner %q: unknown face %c", corner, letter)
+		}
+		normals[i] = n
+		sum = Vec3{X: sum.X + n.X, Y: sum.Y + n.Y, Z: sum.Z + n.Z}
+	}
+	if abs(sum.X) != 1 || abs(sum.Y) != 1 || abs(sum.Z) != 1 {
+		return nil, fmt.Errorf("invalid corner %q: faces must be adjacent", corner)
+	}
+
+	// Ищем поворот, переводящий первую сторону наверх, а угол — в UFR
+	target := Vec3{X: 1, Y: 1, Z: 1}
+	for _, rotation := range cubeRotations() {
+		if applyAxes(normals[0], rotation) != sideNormals[Up] || applyAxes(sum, rotation) != target {
+			continue
+		}
+
+		size := applyAxes(Vec3{X: s.Size.X, Y: s.Size.Y, Z: s.Size.Z}, rotation)
+		rotated := &CubeState{
+			Size:     Size{X: abs(size.X), Y: abs(size.Y), Z: abs(size.Z)},
+			Stickers: make([]Sticker, len(s.Stickers)),
+			Base:     s.Base,
+		}
+		for i, st := range s.Stickers {
+			rotated.Stickers[i] = Sticker{
+				Pos:          applyAxes(st.Pos, rotation),
+				Normal:       applyAxes(st.Normal, rotation),
+				Color:        st.Color,
+				Origin:       applyAxes(st.Origin, rotation),
+				OriginNormal: applyAxes(st.OriginNormal, rotation),
+				Block:        st.Block,
+				Up:           applyAxes(st.Up, rotation),
+			}
+		}
+		return rotated, nil
+	}
+
+	// Поворот всегда находится: угол с первой стороной сверху задаёт ориентацию однозначно
+	return nil, fmt.Errorf("invalid corner %q", corner)
+}
+
+// cubeRotations возвращает все 24 поворота кубика целиком в виде
+// последовательностей поворотов на четверть оборота вокруг осей
+func cubeRotations() [][]Axis {
+	var rotations [][]Axis
+	// Выбираем сторону, которая окажется сверху...
+	for _, up := range [][]Axis{{}, {AxisX}, {AxisX, AxisX}, {AxisX, AxisX, AxisX}, {AxisZ}, {AxisZ, AxisZ, AxisZ}} {
+		// ...и поворачиваем вокруг вертикальной оси
+		for turns := 0; turns < 4; turns++ {
+			rotation := append([]Axis(nil), up...)
+			for i := 0; i < turns; i++ {
+				rotation = append(rotation, AxisY)
+			}
+			rotations = append(rotations, rotation)
+		}
+	}
+	return rotations
+}
+
+// applyAxes последовательно поворачивает вектор вокруг осей
+func applyAxes(v Vec3, axes []Axis) Vec3 {
+	for _, axis := range axes {
+		v = v.rotate(axis)
+	}
+	return v
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
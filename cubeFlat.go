@@ -26,7 +26,12 @@ func (s Side) String() string {
 type FlatCube struct {
 	Size       Size                       // Размер Кубика Рубика XYZ
 	Colors     map[Side][][]rune          // Карта для хранения цветов каждой стороны
+	Blocks     map[Side][][]rune          // Номера склеенных блоков элементов (как Colors; nil — склеек нет)
 	SideParams map[Side]FlatSideParameter // Параметры боковой стороны кубика
+	Arrows     []Arrow                    // Стрелки поверх кубика
+	Twists     []TwistMarker              // Индикаторы ориентации деталей
+	Supercube  []SupercubeMarker          // Метки направления наклеек (суперкубы, кубики с картинками)
+	Rotate     float64                    // Угол поворота картинки в градусах
 }
 
 type FlatSideParameter struct {
@@ -109,9 +114,7 @@ func GenerateFlatCube(cube FlatCube) string {
 	// // // // // СТРОИМ SVG
 
 	// Основной SVG-код для кубика Рубика
-	mianSVG := fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %d %d\">",
-		14+cube.Size.X*49, 14+cube.Size.Y*49)
-	builder.WriteString(mianSVG)
+	GenerateViewBox(&builder, float64(14+cube.Size.X*49), float64(14+cube.Size.Y*49), cube.Rotate)
 
 	// Генерируем фон
 	colorBase := cube.Colors[Base][0][0]
@@ -119,10 +122,14 @@ func GenerateFlatCube(cube FlatCube) string {
 		8+cube.Size.X*49, 8+cube.Size.Y*49, colorMapRGBA[colorBase])
 	builder.WriteString(baseRect)
 
-	// Генерация фронтальной стороны
+	// Генерация фронтальной стороны (склеенные элементы рисуются одной наклейкой)
+	groups, merged := stickerGroups(cube.Colors[Front], cube.Blocks[Front])
 	builder.WriteString("\r\n\t<g id=\"front\">")
 	for y := 0; y < len(cube.Colors[Front]); y++ {
 		for x := 0; x < len(cube.Colors[Front][y]); x++ {
+			if merged[[2]int{y, x}] {
+				continue
+			}
 			color := cube.Colors[Front][y][x]
 
 			startX := 10 + x*49
@@ -133,6 +140,12 @@ func GenerateFlatCube(cube FlatCube) string {
 			builder.WriteString(path)
 		}
 	}
+	for _, group := range groups {
+		y, x := group[0][0], group[0][1]
+		d := mergedStickerPath(group, func(p Point) Point { return Point{X: 7 + p.X*49, Y: 7 + p.Y*49} }, 3, 6.21)
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%s-%dx%d\" d=\"%s\" style=\"fill: %s\"/>",
+			"f", x+1, y+1, d, colorMapRGBA[cube.Colors[Front][y][x]]))
+	}
 	// Закрытие группы
 	builder.WriteString("\r\n\t</g>")
 
@@ -141,8 +154,18 @@ func GenerateFlatCube(cube FlatCube) string {
 	GenerateFlatSide(&builder, cube, Up)
 	GenerateFlatSide(&builder, cube, Right)
 	GenerateFlatSide(&builder, cube, Down)
+
+	// Генерация меток направления наклеек
+	GenerateSupercubeMarkers(&builder, cube.Supercube)
+
+	// Генерация индикаторов ориентации
+	GenerateTwistMarkers(&builder, cube.Twists)
+
+	// Генерация стрелок
+	GenerateArrows(&builder, cube.Arrows, 49)
+
 	// Закрытие SVG
-	builder.WriteString("\r\n</svg>")
+	CloseViewBox(&builder, cube.Rotate)
 
 	// Возвращаем финальную строку SVG
 	return builder.String()
@@ -152,26 +175,45 @@ func GenerateFlatCube(cube FlatCube) string {
 func GenerateFlatSide(builder *strings.Builder, cube FlatCube, side Side) {
 	sideParam := cube.SideParams[side]
 
+	// Сдвиг правой и нижней полосок за фронтальную сторону
+	var shift Point
+	if side == Right {
+		shift.X = float64(8 + cube.Size.X*49)
+	} else if side == Down {
+		shift.Y = float64(8 + cube.Size.Y*49)
+	}
+
+	// Склеенные элементы рисуются одной наклейкой
+	groups, merged := stickerGroups(cube.Colors[side], cube.Blocks[side])
+
 	// Начало группы
 	builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", side.String()))
 	for y := 0; y < len(cube.Colors[side]); y++ {
 		for x := 0; x < len(cube.Colors[side][y]); x++ {
+			if merged[[2]int{y, x}] {
+				continue
+			}
 			colorRune := cube.Colors[side][y][x]
 
-			startX := int(sideParam.Base.X) + x*49
-			startY := int(sideParam.Base.Y) + y*49
-
-			if side == Right {
-				startX += 8 + cube.Size.X*49
-			} else if side == Down {
-				startY += 8 + cube.Size.Y*49
-			}
+			startX := int(sideParam.Base.X+shift.X) + x*49
+			startY := int(sideParam.Base.Y+shift.Y) + y*49
 
 			rect := fmt.Sprintf("\r\n\t\t<rect id=\"%c-%dx%d\" x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"2.32\" style=\"fill: %s\"/>",
 				side.String()[0], x+1, y+1, startX, startY, int(sideParam.Size.X), int(sideParam.Size.Y), colorMapRGBA[colorRune])
 			builder.WriteString(rect)
 		}
 	}
+	for _, group := range groups {
+		y, x := group[0][0], group[0][1]
+		point := func(p Point) Point {
+			return Point{
+				X: sideParam.Base.X + shift.X - 3 + p.X*(sideParam.Size.X+6),
+				Y: sideParam.Base.Y + shift.Y - 3 + p.Y*(sideParam.Size.Y+6),
+			}
+		}
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%c-%dx%d\" d=\"%s\" style=\"fill: %s\"/>",
+			side.String()[0], x+1, y+1, mergedStickerPath(group, point, 3, 2.32), colorMapRGBA[cube.Colors[side][y][x]]))
+	}
 	// Закрытие группы
 	builder.WriteString("\r\n\t</g>")
 }
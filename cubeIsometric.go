@@ -9,7 +9,14 @@ import (
 type IsometricCube struct {
 	Size       Size                            // Размер Кубика Рубика XYZ
 	Colors     map[Side][][]rune               // Карта для хранения цветов каждой стороны
+	Blocks     map[Side][][]rune               // Номера склеенных блоков элементов (как Colors; nil — склеек нет)
 	SideParams map[Side]IsometricSideParameter // Параметры боковой стороны кубика
+	Arrows     []Arrow                         // Стрелки поверх кубика
+	Twists     []TwistMarker                   // Индикаторы ориентации деталей
+	Supercube  []SupercubeMarker               // Метки направления наклеек (суперкубы, кубики с картинками)
+	Layers     LayerSizes                      // Толщины слоёв (для головоломок с неравными слоями)
+	Mono       rune                            // Цвет всех элементов в режиме формы (0 — цвета сторон)
+	Rotate     float64                         // Угол поворота картинки в градусах
 }
 
 // Структура, хранящая параметры для построения элементов на стороне кубика
@@ -17,6 +24,7 @@ type IsometricSideParameter struct {
 	Base   Point  // Базовая точка
 	Multi  Point  // X отвечает за горизонтальный шаг, Y — за вертикальный (0 по оси Y)
 	Offset Point  // Смещение: 0 по X и шаг вниз по Y
+	Center Point  // Смещение центра элемента относительно начальной точки фигуры
 	Drawn  string // Атрибут d тега path в SVG, хранящий в себе построение фигуры
 }
 
@@ -71,13 +79,61 @@ func GenerateIsometricCube(cube IsometricCube) string {
 	var builder strings.Builder
 
 	// Получаем размерность куба (в float64)
-	dX := float64(cube.Size.X)
-	dY := float64(cube.Size.Y)
-	dZ := float64(cube.Size.Z)
-
 	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
 
-	// Считаем размер рамки (viewBox)
+	// Считаем размер рамки (viewBox) и контур основы (base) с учётом толщины слоёв
+	viewBoxSize, basePath := isometricBase(cube.Layers.extent(cube.Size))
+
+	// Считаем положение элементов на сторонах (side) кубика с размерами XxYxZ
+	cube.SideParams = isometricSideParams(cube.Size)
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, cube.Rotate)
+
+	// Создаём основу (base)
+	colorBase := cube.Colors[Base][0][0]
+	builder.WriteString(fmt.Sprintf("\r\n\t<path id=\"base\" d=\"%s\" style=\"fill: %s\"/>", basePath, colorMapRGBA[colorBase]))
+
+	// Создаём стороны (side)
+	for _, side := range []Side{Front, Up, Right} {
+		if cube.Layers.custom() {
+			generateLayeredIsometricSide(&builder, cube, side)
+		} else {
+			GenerateIsometricSide(&builder, cube, side)
+		}
+	}
+
+	// Создаём метки направления наклеек
+	GenerateSupercubeMarkers(&builder, cube.Supercube)
+
+	// Создаём индикаторы ориентации
+	GenerateTwistMarkers(&builder, cube.Twists)
+
+	// Создаём стрелки
+	GenerateArrows(&builder, cube.Arrows, 49)
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, cube.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// isometricCubeBase считает размер рамки (viewBox) и атрибут d контура основы
+// изометрического кубика с размерами XxYxZ
+func isometricCubeBase(size Size) (Point, string) {
+	return isometricBase(LayerSizes{}.extent(size))
+}
+
+// isometricBase считает рамку и контур основы по размерам кубика в шагах 49 точек
+// (у слоёв разной толщины они могут быть дробными)
+func isometricBase(extent Point3) (Point, string) {
+	dX := extent.X
+	dY := extent.Y
+	dZ := extent.Z
+
 	viewBoxSize := Point{
 		X: 2.85 + 42.43*(dZ+dX),
 		Y: -1.38 + 24.5*(dZ+dX) + 49*dY,
@@ -89,67 +145,127 @@ func GenerateIsometricCube(cube IsometricCube) string {
 	LZ := Point{X: 13.58 - 42.43*dZ, Y: -7.83 + 24.5*dZ}
 	M := Point{X: 2.85 + 42.43*(dX+dZ), Y: -8.52 + 49*dY + 24.5*dX}
 
-	// Считаем положение элементов на сторонах (side) кубика с размерами XxYxZ
-	cube.SideParams = map[Side]IsometricSideParameter{
+	path := fmt.Sprintf("M%.2f %.2fv%.2fa15 15 0 00-7.49-13l%.2f %.2fa14.94 14.94 0 00-15 0l%.2f %.2fa15 15 0 00-7.49 13v%.2fa15 15 0 007.49 13l%.2f %.2fa15 15 0 0015 0l%.2f %.2fa15 15 0 007.49-13z",
+		M.X, M.Y, LY.Y, LX.X, LX.Y, LZ.X, LZ.Y, -LY.Y, -LX.X, -LX.Y, -LZ.X, -LZ.Y)
+	return viewBoxSize, path
+}
+
+// IsometricFace задаёт ромб видимой стороны изометрического кубика: левый верхний угол
+// стороны и направления её осей (вправо и вниз), как сторона нарисована на развёртке
+type IsometricFace struct {
+	Origin, AxisX, AxisY Point
+}
+
+// point переводит точку единичного квадрата стороны в координаты картинки
+func (f IsometricFace) point(p Point) Point {
+	return Point{
+		X: f.Origin.X + f.AxisX.X*p.X + f.AxisY.X*p.Y,
+		Y: f.Origin.Y + f.AxisX.Y*p.X + f.AxisY.Y*p.Y,
+	}
+}
+
+// isometricCubeFaces считает ромбы сторон F, U и R по острым вершинам контура основы
+// кубика с размерами XxYxZ. Сторона U повёрнута задней стороной вверх
+func isometricCubeFaces(size Size) map[Side]IsometricFace {
+	return isometricFaces(LayerSizes{}.extent(size))
+}
+
+// isometricFaces считает ромбы сторон по размерам кубика в шагах 49 точек
+func isometricFaces(extent Point3) map[Side]IsometricFace {
+	slope := 24.5 / 42.43
+	front := 1.425 + 42.43*extent.X
+	right := 1.425 + 42.43*extent.Z
+	height := 1.65 + 49*extent.Y
+
+	// Острые вершины контура: UBL, UFL, UFR, UBR и DFL, DFR
+	top := Point{X: right, Y: -2.34}
+	upLeft := Point{X: 0, Y: top.Y + slope*right}
+	center := Point{X: front, Y: upLeft.Y + slope*front}
+	upRight := Point{X: front + right, Y: top.Y + slope*front}
+	downLeft := Point{X: 0, Y: upLeft.Y + height}
+	bottom := Point{X: front, Y: center.Y + height}
+
+	axis := func(from, to Point) Point { return Point{X: to.X - from.X, Y: to.Y - from.Y} }
+	return map[Side]IsometricFace{
+		Front: {Origin: upLeft, AxisX: axis(upLeft, center), AxisY: axis(upLeft, downLeft)},
+		Up:    {Origin: top, AxisX: axis(top, upRight), AxisY: axis(top, upLeft)},
+		Right: {Origin: center, AxisX: axis(center, upRight), AxisY: axis(center, bottom)},
+	}
+}
+
+// isometricSideParams считает положение элементов на сторонах кубика с размерами XxYxZ
+func isometricSideParams(size Size) map[Side]IsometricSideParameter {
+	dX := float64(size.X)
+	dZ := float64(size.Z)
+
+	return map[Side]IsometricSideParameter{
 		Front: {
 			Base:   Point{X: 41.2, Y: 31.48 + 24.5*dZ},
 			Multi:  Point{X: 0, Y: 24.5},
 			Offset: Point{X: 42.43, Y: 49},
+			Center: Point{X: -18.62, Y: 4.1},
 			Drawn:  "v29.69c0,3.67-2.25,5.37-5,3.78l-27.23-15.72c-2.75-1.59-5-5.9-5-9.56v-29.69c0-3.67 2.25-5.37 5-3.78l27.23 15.72c2.75 1.6 5.01 5.9 5.01 9.57z",
 		},
 		Up: {
 			Base:   Point{X: 48.83, Y: 17.92 + 24.5*dZ},
 			Multi:  Point{X: 42.47, Y: -24.5},
 			Offset: Point{X: 42.43, Y: 24.5},
+			Center: Point{X: -4.9, Y: -18.6},
 			Drawn:  "l27.23-15.72c2.75-1.59 2.4-4.39-.78-6.23l-25.7-14.84c-3.18-1.84-8-2-10.79-.45l-27.23 15.72c-2.75 1.59-2.4 4.39.78 6.23l25.71 14.84c3.17 1.84 8.02 2.04 10.78.45z",
 		},
 		Right: {
 			Base:   Point{X: 4.09 + 42.43*dX, Y: 7.98 + 24.5*(dZ+dX)},
 			Multi:  Point{X: 0, Y: -24.5},
 			Offset: Point{X: 42.43, Y: 49},
+			Center: Point{X: 18.62, Y: 4.1},
 			Drawn:  "v29.69c0 3.66 2.25 5.37 5 3.78l27.23-15.72c2.76-1.59 5-5.9 5-9.56v-29.73c0-3.67-2.25-5.37-5-3.78l-27.23 15.72c-2.77 1.6-5 5.89-5 9.6z",
 		},
 	}
+}
 
-	// // // // // СТРОИМ SVG
-
-	// Создаём рамку (viewBox)
-	builder.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %.2f %.2f\">",
-		viewBoxSize.X, viewBoxSize.Y))
-
-	// Создаём основу (base)
-	colorBase := cube.Colors[Base][0][0]
-	builder.WriteString(fmt.Sprintf("\r\n\t<path id=\"base\" d=\"M%.2f %.2fv%.2fa15 15 0 00-7.49-13l%.2f %.2fa14.94 14.94 0 00-15 0l%.2f %.2fa15 15 0 00-7.49 13v%.2fa15 15 0 007.49 13l%.2f %.2fa15 15 0 0015 0l%.2f %.2fa15 15 0 007.49-13z\" style=\"fill: %s\"/>",
-		M.X, M.Y, LY.Y, LX.X, LX.Y, LZ.X, LZ.Y, -LY.Y, -LX.X, -LX.Y, -LZ.X, -LZ.Y, colorMapRGBA[colorBase]))
-
-	// Создаём стороны (side)
-	GenerateIsometricSide(&builder, cube, Front)
-	GenerateIsometricSide(&builder, cube, Up)
-	GenerateIsometricSide(&builder, cube, Right)
-
-	// Закрываем рамку (viewBox)
-	builder.WriteString("\r\n</svg>")
-
-	// Возвращаем сгенерированную SVG
-	return builder.String()
+// stickerCenter возвращает центр элемента в строке x и столбце y стороны side
+func (p IsometricSideParameter) stickerCenter(x, y int) Point {
+	return Point{
+		X: p.Base.X + float64(x)*p.Multi.X + float64(y)*p.Offset.X + p.Center.X,
+		Y: p.Base.Y + float64(y)*p.Multi.Y + float64(x)*p.Offset.Y + p.Center.Y,
+	}
 }
 
 func GenerateIsometricSide(builder *strings.Builder, cube IsometricCube, side Side) {
 	sideParam := cube.SideParams[side]
 
+	// Склеенные элементы рисуются одной наклейкой
+	groups, merged := stickerGroups(cube.Colors[side], cube.Blocks[side])
+
 	// Начало группы
 	builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", side.String()))
 	for x := 0; x < len(cube.Colors[side]); x++ {
 		for y := 0; y < len(cube.Colors[side][x]); y++ {
+			if merged[[2]int{x, y}] {
+				continue
+			}
 			color := cube.Colors[side][x][y]
 			startX := sideParam.Base.X + float64(x)*sideParam.Multi.X + float64(y)*sideParam.Offset.X
 			startY := sideParam.Base.Y + float64(y)*sideParam.Multi.Y + float64(x)*sideParam.Offset.Y
 
 			path := fmt.Sprintf("\r\n\t\t<path id=\"%c-%dx%d\" d=\"M%.2f %.2f %s\" style=\"fill: %s\"/>",
-				side.String()[0], x+1, y+1, startX, startY, sideParam.Drawn, colorMapRGBA[color])
+				side.String()[0], x+1, y+1, startX, startY, sideParam.Drawn, cube.stickerFill(side, color))
 			builder.WriteString(path)
 		}
 	}
+	cube.generateMergedStickers(builder, side, groups)
 	// Закрытие группы
 	builder.WriteString("\r\n\t</g>")
 }
+
+// Затемнение сторон в режиме формы: верхняя сторона светлее всех, правая темнее всех
+var monoShades = map[Side]float64{Up: 1, Front: 0.85, Right: 0.7}
+
+// stickerFill возвращает цвет элемента color на стороне side. В режиме формы все элементы
+// одного цвета Mono, а стороны различаются затемнением
+func (cube IsometricCube) stickerFill(side Side, color rune) string {
+	if cube.Mono == 0 {
+		return colorMapRGBA[color]
+	}
+	return shadeColor(colorMapRGBA[cube.Mono], monoShades[side])
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Наибольшая толщина слоя (в шагах 49 точек)
+const maxLayerSize = 4
+
+// LayerSizes хранит толщины слоёв кубика по осям в шагах 49 точек, как у головоломок
+// с неравными слоями (Mirror Blocks и т.п.). Пустой список — все слои оси толщиной 1
+type LayerSizes struct {
+	X []float64 // Слои слева направо
+	Y []float64 // Слои сверху вниз
+	Z []float64 // Слои спереди назад
+}
+
+// ParseLayerSizes парсит толщины слоёв по осям: списки чисел через запятую, например 1.2,1,0.8.
+// Число слоёв в списке должно совпадать с размером кубика по этой оси
+func ParseLayerSizes(pX, pY, pZ string, size Size) (LayerSizes, error) {
+	var layers LayerSizes
+	axes := []struct {
+		name   string
+		value  string
+		count  int
+		layers *[]float64
+	}{
+		{"x", pX, size.X, &layers.X},
+		{"y", pY, size.Y, &layers.Y},
+		{"z", pZ, size.Z, &layers.Z},
+	}
+	for _, axis := range axes {
+		if axis.value == "" {
+			continue
+		}
+		values := strings.Split(axis.value, ",")
+		if len(values) != axis.count {
+			return LayerSizes{}, fmt.Errorf("invalid %s layers: expected %d values, got %d", axis.name, axis.count, len(values))
+		}
+		for _, v := range values {
+			value, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || math.IsNaN(value) || value <= 0 || value > maxLayerSize {
+				return LayerSizes{}, fmt.Errorf("invalid %s layer size %q, expected a number greater than 0 and at most %d", axis.name, v, maxLayerSize)
+			}
+			*axis.layers = append(*axis.layers, value)
+		}
+	}
+	return layers, nil
+}
+
+// ParseMonoColor парсит цвет режима формы: одна буква цвета
+func ParseMonoColor(pMono string) (rune, error) {
+	color := []rune(strings.ToUpper(pMono))
+	if len(color) != 1 {
+		return 0, fmt.Errorf("invalid mono color %q, expected one color letter", pMono)
+	}
+	if _, ok := colorMapRGBA[color[0]]; !ok {
+		return 0, fmt.Errorf("unknown mono color %q", pMono)
+	}
+	return color[0], nil
+}
+
+// custom сообщает, заданы ли толщины хотя бы по одной оси
+func (l LayerSizes) custom() bool {
+	return l.X != nil || l.Y != nil || l.Z != nil
+}
+
+// extent возвращает размеры кубика size в шагах 49 точек с учётом толщины слоёв
+func (l LayerSizes) extent(size Size) Point3 {
+	return Point3{X: layerTotal(l.X, size.X), Y: layerTotal(l.Y, size.Y), Z: layerTotal(l.Z, size.Z)}
+}
+
+// layerTotal возвращает суммарную толщину слоёв оси из count слоёв
+func layerTotal(layers []float64, count int) float64 {
+	if layers == nil {
+		return float64(count)
+	}
+	total := 0.0
+	for _, v := range layers {
+		total += v
+	}
+	return total
+}
+
+// layerBounds возвращает границы слоёв оси в долях её длины: count+1 чисел от 0 до 1.
+// При reverse слои идут в обратном порядке
+func layerBounds(layers []float64, count int, reverse bool) []float64 {
+	total := layerTotal(layers, count)
+	bounds := []float64{0}
+	for i := 0; i < count; i++ {
+		j := i
+		if reverse {
+			j = count - 1 - i
+		}
+		v := 1.0
+		if layers != nil {
+			v = layers[j]
+		}
+		bounds = append(bounds, bounds[i]+v/total)
+	}
+	return bounds
+}
+
+// sideBounds возвращает границы столбцов и строк стороны side в осях ромба стороны
+// (см. isometricCubeFaces). Сторона U идёт строками от задней стороны к передней
+func (l LayerSizes) sideBounds(side Side, size Size) ([]float64, []float64) {
+	switch side {
+	case Up:
+		return layerBounds(l.X, size.X, false), layerBounds(l.Z, size.Z, true)
+	case Right:
+		return layerBounds(l.Z, size.Z, false), layerBounds(l.Y, size.Y, false)
+	default:
+		return layerBounds(l.X, size.X, false), layerBounds(l.Y, size.Y, false)
+	}
+}
+
+// layeredCell возвращает ромб стороны face, а также центр и векторы шага клетки
+// в столбце col и строке row
+func layeredCell(face IsometricFace, cols, rows []float64, col, row int) ([]Point, StickerFrame) {
+	x0, x1 := cols[col], cols[col+1]
+	y0, y1 := rows[row], rows[row+1]
+	polygon := []Point{
+		face.point(Point{X: x0, Y: y0}), face.point(Point{X: x1, Y: y0}),
+		face.point(Point{X: x1, Y: y1}), face.point(Point{X: x0, Y: y1}),
+	}
+	frame := StickerFrame{
+		Center: face.point(Point{X: (x0 + x1) / 2, Y: (y0 + y1) / 2}),
+		U:      Point{X: face.AxisX.X * (x1 - x0), Y: face.AxisX.Y * (x1 - x0)},
+		V:      Point{X: face.AxisY.X * (y1 - y0), Y: face.AxisY.Y * (y1 - y0)},
+	}
+	return polygon, frame
+}
+
+// generateLayeredIsometricSide рисует сторону side изометрического кубика со слоями разной толщины.
+// Элементы — ромбы клеток, сжатые на отступ, как наклейки головоломок на контуре кубика
+func generateLayeredIsometricSide(builder *strings.Builder, cube IsometricCube, side Side) {
+	face := isometricFaces(cube.Layers.extent(cube.Size))[side]
+	cols, rows := cube.Layers.sideBounds(side, cube.Size)
+
+	// Склеенные элементы рисуются одной наклейкой
+	groups, merged := stickerGroups(cube.Colors[side], cube.Blocks[side])
+
+	// Начало группы
+	builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", side.String()))
+	for x := 0; x < len(cube.Colors[side]); x++ {
+		for y := 0; y < len(cube.Colors[side][x]); y++ {
+			if merged[[2]int{x, y}] {
+				continue
+			}
+			// Верхняя сторона хранится по столбцам: x — слой слева направо, y — глубина спереди
+			col, row := y, x
+			if side == Up {
+				col, row = x, cube.Size.Z-1-y
+			}
+			polygon, _ := layeredCell(face, cols, rows, col, row)
+
+			path := fmt.Sprintf("\r\n\t\t<path id=\"%c-%dx%d\" d=\"%s\" style=\"fill: %s\"/>",
+				side.String()[0], x+1, y+1, roundedStickerPath(polygon, cornerCubeGap, cornerCubeRound), cube.stickerFill(side, cube.Colors[side][x][y]))
+			builder.WriteString(path)
+		}
+	}
+	cube.generateMergedStickers(builder, side, groups)
+	// Закрытие группы
+	builder.WriteString("\r\n\t</g>")
+}
+
+// layeredStickerFrame находит элемент изометрического вида со слоями разной толщины
+// (нумерация как у stickerFrame)
+func (cube *IsometricCube) layeredStickerFrame(ref StickerRef) (StickerFrame, bool) {
+	X, Y, Z := cube.Size.X, cube.Size.Y, cube.Size.Z
+
+	var side Side
+	var rowCount, colCount int
+	switch ref.Face {
+	case 'F':
+		side, rowCount, colCount = Front, Y, X
+	case 'U':
+		side, rowCount, colCount = Up, Z, X
+	case 'R':
+		side, rowCount, colCount = Right, Y, Z
+	default:
+		return StickerFrame{}, false
+	}
+	if ref.Index >= rowCount*colCount {
+		return StickerFrame{}, false
+	}
+
+	face := isometricFaces(cube.Layers.extent(cube.Size))[side]
+	cols, rows := cube.Layers.sideBounds(side, cube.Size)
+	r, c := ref.Index/colCount, ref.Index%colCount
+	_, frame := layeredCell(face, cols, rows, c, r)
+	frame.Row, frame.Col, frame.Rows, frame.Cols = r, c, rowCount, colCount
+	return frame, true
+}
+
+// generateMergedStickers рисует склеенные элементы стороны side одной наклейкой на группу.
+// Вершины контура группы лежат на линиях сетки элементов и переводятся на ромб стороны
+func (cube IsometricCube) generateMergedStickers(builder *strings.Builder, side Side, groups [][][2]int) {
+	if len(groups) == 0 {
+		return
+	}
+	face := isometricFaces(cube.Layers.extent(cube.Size))[side]
+	cols, rows := cube.Layers.sideBounds(side, cube.Size)
+	point := func(p Point) Point {
+		i, j := int(p.Y), int(p.X)
+		if side == Up {
+			// Верхняя сторона хранится по столбцам: первый индекс — слой слева направо, второй — глубина спереди
+			return face.point(Point{X: cols[i], Y: rows[cube.Size.Z-j]})
+		}
+		return face.point(Point{X: cols[j], Y: rows[i]})
+	}
+
+	for _, group := range groups {
+		x, y := group[0][0], group[0][1]
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%c-%dx%d\" d=\"%s\" style=\"fill: %s\"/>",
+			side.String()[0], x+1, y+1, mergedStickerPath(group, point, cornerCubeGap, cornerCubeRound), cube.stickerFill(side, cube.Colors[side][x][y])))
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Axis ось вращения слоя
+type Axis int
+
+const (
+	AxisX Axis = iota // Ось R-L
+	AxisY             // Ось U-D
+	AxisZ             // Ось F-B
+)
+
+// Move хранит один ход: поворот слоёв вдоль оси
+type Move struct {
+	Name  string // Исходная запись хода
+	Axis  Axis   // Ось вращения
+	Min   int    // Нижняя граница слоёв (удвоенные координаты центров кубиков)
+	Max   int    // Верхняя граница слоёв
+	Turns int    // Число четвертей оборота по часовой стрелке, если смотреть с положительной стороны оси (1..3)
+}
+
+// Описание хода по букве: ось, сторона и направление по часовой стрелке
+type moveFace struct {
+	Axis Axis
+	Sign int // +1, если сторона лежит на положительном конце оси, -1 — на отрицательном, 0 — средний слой
+	Dir  int // Направление поворота в четвертях относительно положительной стороны оси
+}
+
+var moveFaces = map[rune]moveFace{
+	'R': {Axis: AxisX, Sign: 1, Dir: 1},
+	'L': {Axis: AxisX, Sign: -1, Dir: 3},
+	'U': {Axis: AxisY, Sign: 1, Dir: 1},
+	'D': {Axis: AxisY, Sign: -1, Dir: 3},
+	'F': {Axis: AxisZ, Sign: 1, Dir: 1},
+	'B': {Axis: AxisZ, Sign: -1, Dir: 3},
+	'M': {Axis: AxisX, Dir: 3},
+	'E': {Axis: AxisY, Dir: 3},
+	'S': {Axis: AxisZ, Dir: 1},
+	'x': {Axis: AxisX, Dir: 1},
+	'y': {Axis: AxisY, Dir: 1},
+	'z': {Axis: AxisZ, Dir: 1},
+}
+
+// Регулярное выражение для одного хода: [n[-m]]буква[w][число]['].
+// Апостроф может быть записан типографским символом
+var moveRegexp = regexp.MustCompile(`^(\d+)?(?:-(\d+))?([RLUDFBMESxyzrludfb])(w)?(\d+)?(['’]?)`)
+
+// ParseAlgorithm разбирает алгоритм в нотации WCA для кубоида заданного размера
+func ParseAlgorithm(alg string, size Size) ([]Move, error) {
+	var moves []Move
+
+	rest := alg
+	for {
+		// Пропускаем пробелы и скобки, они не влияют на ходы
+		rest = strings.TrimLeft(rest, " \t\r\n()[]+_")
+		if rest == "" {
+			break
+		}
+
+		m := moveRegexp.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, fmt.Errorf("invalid move notation near %q", rest)
+		}
+		rest = rest[len(m[0]):]
+
+		move, err := parseMove(m, size)
+		if err != nil {
+			return nil, err
+		}
+		if move.Turns != 0 {
+			moves = append(moves, move)
+		}
+	}
+
+	return moves, nil
+}
+
+// parseMove преобразует разобранную запись хода в Move
+func parseMove(m []string, size Size) (Move, error) {
+	letter := []rune(m[3])[0]
+	wide := m[4] != ""
+
+	// Строчные буквы сторон означают широкий ход (по умолчанию на два слоя)
+	if strings.ContainsRune("rludfb", letter) {
+		if wide {
+			return Move{}, fmt.Errorf("invalid move %q", m[0])
+		}
+		letter = []rune(strings.ToUpper(string(letter)))[0]
+		wide = true
+	}
+	face := moveFaces[letter]
+
+	// Количество слоёв вдоль оси
+	extent := [...]int{size.X, size.Y, size.Z}[face.Axis]
+
+	move := Move{Name: m[0], Axis: face.Axis}
+
+	// Определяем, какие слои поворачиваются (номера слоёв считаются от стороны, начиная с 1)
+	from, to := 1, 1
+	switch {
+	case face.Sign == 0 && strings.ContainsRune("xyz", letter):
+		// Поворот всего кубика
+		if m[1] != "" || wide {
+			return Move{}, fmt.Errorf("invalid rotation %q", m[0])
+		}
+		from, to = 1, extent
+	case face.Sign == 0:
+		// Средние слои: все, кроме внешних
+		if extent < 3 || m[1] != "" || wide {
+			return Move{}, fmt.Errorf("move %q requires at least 3 layers", m[0])
+		}
+		from, to = 2, extent-1
+	default:
+		n, _ := strconv.Atoi(m[1])
+		k, _ := strconv.Atoi(m[2])
+		switch {
+		case m[2] != "":
+			from, to = n, k
+		case m[1] != "" && wide:
+			from, to = 1, n
+		case m[1] != "":
+			from, to = n, n
+		case wide:
+			from, to = 1, 2
+		}
+	}
+	if from < 1 || to < from || to > extent {
+		return Move{}, fmt.Errorf("move %q is out of range for %d layers", m[0], extent)
+	}
+
+	// Переводим номера слоёв в координаты центров кубиков
+	if face.Sign < 0 {
+		move.Min, move.Max = -extent+2*from-1, -extent+2*to-1
+	} else {
+		move.Min, move.Max = extent-2*to+1, extent-2*from+1
+	}
+
+	// Количество четвертей оборота
+	amount := 1
+	if m[5] != "" {
+		amount, _ = strconv.Atoi(m[5])
+	}
+	if m[6] != "" {
+		amount = -amount
+	}
+	move.Turns = ((face.Dir*amount)%4 + 4) % 4
+
+	// На кубоиде четверть оборота возможна только для квадратного сечения
+	if move.Turns%2 == 1 {
+		a, b := size.Y, size.Z
+		switch face.Axis {
+		case AxisY:
+			a, b = size.X, size.Z
+		case AxisZ:
+			a, b = size.X, size.Y
+		}
+		if a != b {
+			return Move{}, fmt.Errorf("move %q is not possible on a %dx%dx%d cuboid", m[0], size.X, size.Y, size.Z)
+		}
+	}
+
+	return move, nil
+}
+
+// ParseRotation разбирает последовательность поворотов всего кубика (x, y, z)
+func ParseRotation(rotation string, size Size) ([]Move, error) {
+	moves, err := ParseAlgorithm(rotation, size)
+	if err != nil {
+		return nil, err
+	}
+	for _, move := range moves {
+		if !strings.ContainsAny(move.Name, "xyz") {
+			return nil, fmt.Errorf("expected only cube rotations (x, y, z), got %q", move.Name)
+		}
+	}
+	return moves, nil
+}
+
+// InvertAlgorithm возвращает обратную последовательность ходов
+func InvertAlgorithm(moves []Move) []Move {
+	inverse := make([]Move, len(moves))
+	for i, move := range moves {
+		move.Turns = (4 - move.Turns) % 4
+		inverse[len(moves)-1-i] = move
+	}
+	return inverse
+}
+
+// rotate поворачивает вектор на четверть оборота по часовой стрелке вокруг оси
+func (v Vec3) rotate(axis Axis) Vec3 {
+	switch axis {
+	case AxisX:
+		return Vec3{X: v.X, Y: v.Z, Z: -v.Y}
+	case AxisY:
+		return Vec3{X: -v.Z, Y: v.Y, Z: v.X}
+	default:
+		return Vec3{X: v.Y, Y: -v.X, Z: v.Z}
+	}
+}
+
+// coord возвращает координату вектора вдоль оси
+func (v Vec3) coord(axis Axis) int {
+	return [...]int{v.X, v.Y, v.Z}[axis]
+}
+
+// ApplyMoves применяет ходы к состоянию кубика. Ход, который разрезает склеенный блок,
+// прерывает применение с ошибкой
+func (s *CubeState) ApplyMoves(moves []Move) error {
+	for _, move := range moves {
+		if err := s.checkBandage(move); err != nil {
+			return err
+		}
+		for i := range s.Stickers {
+			st := &s.Stickers[i]
+			c := st.Pos.coord(move.Axis)
+			if c < move.Min || c > move.Max {
+				continue
+			}
+			for t := 0; t < move.Turns; t++ {
+				st.Pos = st.Pos.rotate(move.Axis)
+				st.Normal = st.Normal.rotate(move.Axis)
+				st.Up = st.Up.rotate(move.Axis)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyAlgorithm разбирает алгоритм и применяет его к состоянию кубика
+func (s *CubeState) ApplyAlgorithm(alg string) error {
+	moves, err := ParseAlgorithm(alg, s.Size)
+	if err != nil {
+		return err
+	}
+	return s.ApplyMoves(moves)
+}
+
+// ApplyCase применяет к состоянию кубика обратный алгоритм,
+// чтобы получить случай, который этот алгоритм решает
+func (s *CubeState) ApplyCase(alg string) error {
+	moves, err := ParseAlgorithm(alg, s.Size)
+	if err != nil {
+		return err
+	}
+	return s.ApplyMoves(InvertAlgorithm(moves))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// solvedState возвращает собранный кубик со стандартной схемой цветов
+func solvedState(t *testing.T, dimensions string) *CubeState {
+	t.Helper()
+	state, err := ParseCubeStateParams(dimensions, "")
+	if err != nil {
+		t.Fatalf("ParseCubeStateParams(%q): %v", dimensions, err)
+	}
+	return state
+}
+
+// faceLine возвращает цвета строки (row >= 0) или столбца (col >= 0) стороны в раскладке развёртки
+func faceLine(faces map[Side][][]rune, side Side, row, col int) string {
+	var line []rune
+	for r := range faces[side] {
+		for c := range faces[side][r] {
+			if r == row || c == col {
+				line = append(line, faces[side][r][c])
+			}
+		}
+	}
+	return string(line)
+}
+
+func TestSingleMoveFacelets(t *testing.T) {
+	type line struct {
+		side     Side
+		row, col int
+		want     string
+	}
+	tests := []struct {
+		move  string
+		lines []line
+	}{
+		{"R", []line{{Up, -1, 2, "GGG"}, {Front, -1, 2, "YYY"}, {Down, -1, 2, "BBB"}, {Back, -1, 0, "WWW"}, {Right, 1, -1, "RRR"}}},
+		{"U", []line{{Front, 0, -1, "RRR"}, {Left, 0, -1, "GGG"}, {Back, 0, -1, "OOO"}, {Right, 0, -1, "BBB"}, {Front, 1, -1, "GGG"}}},
+		{"F", []line{{Up, 2, -1, "OOO"}, {Right, -1, 0, "WWW"}, {Down, 0, -1, "RRR"}, {Left, -1, 2, "YYY"}, {Up, 1, -1, "WWW"}}},
+		{"M", []line{{Up, -1, 1, "BBB"}, {Front, -1, 1, "WWW"}, {Down, -1, 1, "GGG"}, {Back, -1, 1, "YYY"}, {Front, -1, 0, "GGG"}}},
+		{"E", []line{{Front, 1, -1, "OOO"}, {Right, 1, -1, "GGG"}, {Back, 1, -1, "RRR"}, {Left, 1, -1, "BBB"}, {Front, 0, -1, "GGG"}}},
+		{"S", []line{{Up, 1, -1, "OOO"}, {Right, -1, 1, "WWW"}, {Down, 1, -1, "RRR"}, {Left, -1, 1, "YYY"}, {Up, 0, -1, "WWW"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.move, func(t *testing.T) {
+			state := solvedState(t, "3x3x3")
+			if err := state.ApplyAlgorithm(tt.move); err != nil {
+				t.Fatalf("ApplyAlgorithm(%q): %v", tt.move, err)
+			}
+			faces := state.Faces()
+			for _, l := range tt.lines {
+				if got := faceLine(faces, l.side, l.row, l.col); got != l.want {
+					t.Errorf("%s row %d col %d = %q, want %q", l.side, l.row, l.col, got, l.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSexyMoveOrder(t *testing.T) {
+	state := solvedState(t, "3x3x3")
+	solved := append([]Sticker(nil), state.Stickers...)
+
+	for i := 1; i <= 6; i++ {
+		if err := state.ApplyAlgorithm("R U R' U'"); err != nil {
+			t.Fatal(err)
+		}
+		identity := reflect.DeepEqual(state.Stickers, solved)
+		if identity != (i == 6) {
+			t.Errorf("after %d repetitions identity = %v", i, identity)
+		}
+	}
+}
+
+func TestCaseThenAlgIsSolved(t *testing.T) {
+	tests := []struct {
+		dimensions string
+		alg        string
+	}{
+		{"3x3x3", "R U R' U R U2 R'"},
+		{"3x3x3", "M2 U M U2 M' U M2"},
+		{"3x3x3", "x R' U R' D2 R U' R' D2 R2 x'"},
+		{"3x3x3", "r U R' U' r' F R F' E S' y"},
+		{"4x4x4", "Rw U2 3Rw' 2-3Lw2 Uw'"},
+		{"2x3x4", "U2 R2 F2 D2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dimensions+" "+tt.alg, func(t *testing.T) {
+			state := solvedState(t, tt.dimensions)
+			solved := state.Faces()
+			if err := state.ApplyCase(tt.alg); err != nil {
+				t.Fatalf("ApplyCase: %v", err)
+			}
+			if err := state.ApplyAlgorithm(tt.alg); err != nil {
+				t.Fatalf("ApplyAlgorithm: %v", err)
+			}
+			if got := state.Faces(); !reflect.DeepEqual(got, solved) {
+				t.Errorf("case + alg is not solved: %v", got)
+			}
+		})
+	}
+}
+
+func TestParseAlgorithmRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		dimensions string
+		alg        string
+	}{
+		{"3x3x3", "Q"},
+		{"3x3x3", "R U X"},
+		{"3x3x3", "rw"},
+		{"3x3x3", "4R"},
+		{"3x3x3", "2-4Rw"},
+		{"3x3x3", "2x"},
+		{"2x2x2", "M"},
+		{"2x3x4", "R"},
+		{"2x3x4", "y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dimensions+" "+tt.alg, func(t *testing.T) {
+			state := solvedState(t, tt.dimensions)
+			if _, err := ParseAlgorithm(tt.alg, state.Size); err == nil {
+				t.Errorf("ParseAlgorithm(%q) returned no error", tt.alg)
+			}
+		})
+	}
+}
+
+func TestParseRotationRejectsMoves(t *testing.T) {
+	if _, err := ParseRotation("x2 R", Size{X: 3, Y: 3, Z: 3}); err == nil {
+		t.Error("ParseRotation accepted a face move")
+	}
+	if _, err := ParseRotation("x2 y'", Size{X: 3, Y: 3, Z: 3}); err != nil {
+		t.Errorf("ParseRotation rejected rotations: %v", err)
+	}
+}
+
+func TestParseCubeStateParamsRejectsBadDimensions(t *testing.T) {
+	for _, dimensions := range []string{"3", "3x3x3x3", "ax3x3", "0x3x3", "65x3x3"} {
+		if _, err := ParseCubeStateParams(dimensions, ""); err == nil {
+			t.Errorf("ParseCubeStateParams(%q) returned no error", dimensions)
+		}
+	}
+}
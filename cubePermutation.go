@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ResetOrigin считает текущее положение наклеек собранным
+// (используется после поворота кубика целиком)
+func (s *CubeState) ResetOrigin() {
+	for i := range s.Stickers {
+		s.Stickers[i].Origin = s.Stickers[i].Pos
+		s.Stickers[i].OriginNormal = s.Stickers[i].Normal
+	}
+}
+
+// PermutationArrows строит стрелки перестановки деталей верхнего слоя:
+// каждая стрелка ведёт от текущего положения детали к месту, где она стоит в собранном кубике.
+// kind — "all", "corners" или "edges". Циклы из двух деталей рисуются одной двусторонней стрелкой
+func (s *CubeState) PermutationArrows(kind string, template ArrowSpec) ([]ArrowSpec, error) {
+	if kind != "all" && kind != "corners" && kind != "edges" {
+		return nil, fmt.Errorf("unknown automatic arrows type %q", kind)
+	}
+
+	// Куда должна переместиться каждая деталь верхнего слоя
+	target := make(map[Vec3]Vec3)
+	for _, st := range s.Stickers {
+		if !s.isTop(st.Pos) || st.Pos == st.Origin || !s.isTop(st.Origin) {
+			continue
+		}
+		if (kind == "corners" && !s.isCorner(st.Pos)) || (kind == "edges" && !s.isEdge(st.Pos)) {
+			continue
+		}
+		target[st.Pos] = st.Origin
+	}
+
+	// Номер элемента верхней стороны для детали
+	index := func(p Vec3) int {
+		_, r, c := s.stickerIndex(p, sideNormals[Up])
+		return r*s.Size.X + c
+	}
+	ref := func(p Vec3) StickerRef {
+		return StickerRef{Face: 'U', Index: index(p)}
+	}
+
+	// Обходим детали в порядке номеров, чтобы стрелки всегда шли в одном порядке
+	starts := make([]Vec3, 0, len(target))
+	for p := range target {
+		starts = append(starts, p)
+	}
+	sort.Slice(starts, func(i, j int) bool { return index(starts[i]) < index(starts[j]) })
+
+	var specs []ArrowSpec
+	visited := make(map[Vec3]bool)
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+
+		// Собираем цикл
+		cycle := []Vec3{start}
+		visited[start] = true
+		for next := target[start]; next != start; next = target[next] {
+			if _, ok := target[next]; !ok || visited[next] {
+				break
+			}
+			cycle = append(cycle, next)
+			visited[next] = true
+		}
+
+		if len(cycle) == 2 && target[cycle[1]] == cycle[0] {
+			spec := template
+			spec.Stickers = []StickerRef{ref(cycle[0]), ref(cycle[1])}
+			spec.Double = true
+			specs = append(specs, spec)
+			continue
+		}
+		for _, p := range cycle {
+			spec := template
+			spec.Stickers = []StickerRef{ref(p), ref(target[p])}
+			specs = append(specs, spec)
+		}
+	}
+
+	return specs, nil
+}
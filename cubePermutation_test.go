@@ -0,0 +1,89 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPermutationArrows(t *testing.T) {
+	tests := []struct {
+		name string
+		alg  string // Случай, который решает алгоритм (применяется обратный)
+		kind string
+		want []ArrowSpec
+	}{
+		{"solved", "", "all", nil},
+		{"T-perm", "R U R' U' R' F R2 U' R' U' R U R' F'", "all", []ArrowSpec{
+			{Stickers: []StickerRef{{'U', 2}, {'U', 8}}, Double: true, Color: 'K'},
+			{Stickers: []StickerRef{{'U', 3}, {'U', 5}}, Double: true, Color: 'K'},
+		}},
+		{"T-perm corners", "R U R' U' R' F R2 U' R' U' R U R' F'", "corners", []ArrowSpec{
+			{Stickers: []StickerRef{{'U', 2}, {'U', 8}}, Double: true, Color: 'K'},
+		}},
+		{"Ua-perm corners", "R U' R U R U R U' R' U' R2", "corners", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := solvedState(t, "3x3x3")
+			if err := state.ApplyCase(tt.alg); err != nil {
+				t.Fatal(err)
+			}
+			got, err := state.PermutationArrows(tt.kind, ArrowSpec{Color: 'K'})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PermutationArrows = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermutationArrowsCycles(t *testing.T) {
+	tests := []struct {
+		alg    string
+		kind   string
+		arrows int
+	}{
+		{"R U' R U R U R U' R' U' R2", "edges", 3}, // Ua: цикл из трёх рёбер
+		{"U", "all", 8},  // Два цикла из четырёх деталей
+		{"U2", "all", 4}, // Четыре обмена, каждый — одна двусторонняя стрелка
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			state := solvedState(t, "3x3x3")
+			if err := state.ApplyCase(tt.alg); err != nil {
+				t.Fatal(err)
+			}
+			got, err := state.PermutationArrows(tt.kind, ArrowSpec{Color: 'K'})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.arrows {
+				t.Fatalf("got %d arrows, want %d: %+v", len(got), tt.arrows, got)
+			}
+
+			// Каждая деталь цикла — начало ровно одной стрелки и конец ровно одной
+			from, to := make(map[StickerRef]int), make(map[StickerRef]int)
+			for _, spec := range got {
+				from[spec.Stickers[0]]++
+				to[spec.Stickers[1]]++
+				if spec.Double {
+					from[spec.Stickers[1]]++
+					to[spec.Stickers[0]]++
+				}
+			}
+			if !reflect.DeepEqual(from, to) {
+				t.Errorf("arrows do not form cycles: from %v, to %v", from, to)
+			}
+		})
+	}
+}
+
+func TestPermutationArrowsRejectsUnknownKind(t *testing.T) {
+	if _, err := solvedState(t, "3x3x3").PermutationArrows("centers", ArrowSpec{}); err == nil {
+		t.Error("PermutationArrows accepted an unknown kind")
+	}
+}
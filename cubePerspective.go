@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Point3 точка или вектор в пространстве сцены (в удвоенных единицах кубика, как Vec3)
+type Point3 struct {
+	X, Y, Z float64
+}
+
+// Camera положение камеры для перспективного вида (углы в градусах)
+type Camera struct {
+	Yaw   float64 // Поворот вокруг вертикальной оси: положительный открывает правую сторону
+	Pitch float64 // Наклон: положительный открывает верхнюю сторону
+	Roll  float64 // Поворот вокруг оси взгляда по часовой стрелке
+	FOV   float64 // Угол обзора
+}
+
+// Камера по умолчанию: видны передняя, правая и верхняя стороны
+var defaultCamera = Camera{Yaw: 35, Pitch: 30, Roll: 0, FOV: 35}
+
+type PerspectiveCube struct {
+	Size     Size      // Размер Кубика Рубика XYZ
+	Stickers []Sticker // Наклейки всех сторон
+	Base     rune      // Цвет основы (base)
+	Camera   Camera    // Положение камеры
+	Rotate   float64   // Угол поворота картинки в градусах
+}
+
+// Параметры построения перспективного вида (в удвоенных единицах кубика)
+const (
+	perspectiveScale   = 24.5 // Масштаб: кубик (2 единицы) занимает 49 точек, как в остальных видах
+	perspectiveSticker = 0.88 // Половина стороны наклейки
+	perspectiveRound   = 0.25 // Радиус скругления наклейки
+	perspectiveBorder  = 0.3  // Толщина обводки основы
+)
+
+// Perspective возвращает перспективный вид кубика для GeneratePerspectiveCube
+func (s *CubeState) Perspective(camera Camera) PerspectiveCube {
+	return PerspectiveCube{Size: s.Size, Stickers: s.Stickers, Base: s.Base, Camera: camera}
+}
+
+// perspectiveFace видимая сторона кубика, готовая к построению
+type perspectiveFace struct {
+	Side     Side     // Сторона кубика
+	Depth    float64  // Расстояние от камеры до центра стороны
+	Outline  []Point  // Контур стороны основы
+	Stickers []string // Атрибуты d наклеек
+	Colors   []rune   // Цвета наклеек
+}
+
+// GeneratePerspectiveCube генерирует перспективную SVG картинку кубика
+func GeneratePerspectiveCube(cube PerspectiveCube) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Расстояние до камеры подбирается так, чтобы описанная сфера кубика помещалась в угол обзора
+	half := Point3{X: float64(cube.Size.X), Y: float64(cube.Size.Y), Z: float64(cube.Size.Z)}
+	radius := math.Sqrt(half.X*half.X + half.Y*half.Y + half.Z*half.Z)
+	distance := radius / math.Sin(cube.Camera.FOV*math.Pi/360)
+	view := newCameraView(cube.Camera, distance)
+
+	// Собираем видимые стороны
+	var faces []*perspectiveFace
+	for _, side := range stateSides {
+		n := sideNormals[side]
+		normal := Point3{X: float64(n.X), Y: float64(n.Y), Z: float64(n.Z)}
+		u, v := faceTangents(n)
+		center := Point3{X: normal.X * half.X, Y: normal.Y * half.Y, Z: normal.Z * half.Z}
+
+		// Сторона видна, если камера находится перед её плоскостью
+		if !view.facing(center, normal) {
+			continue
+		}
+
+		face := &perspectiveFace{Side: side, Depth: view.depth(center)}
+		su, sv := u.dot(half), v.dot(half)
+		for _, k := range [4][2]float64{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+			corner := center.add(u.scale(k[0] * su)).add(v.scale(k[1] * sv))
+			face.Outline = append(face.Outline, view.project(corner))
+		}
+
+		// Наклейки стороны
+		for _, st := range cube.Stickers {
+			if st.Normal != n {
+				continue
+			}
+			p := Point3{X: float64(st.Pos.X + st.Normal.X), Y: float64(st.Pos.Y + st.Normal.Y), Z: float64(st.Pos.Z + st.Normal.Z)}
+			face.Stickers = append(face.Stickers, view.roundedSquare(p, u, v))
+			face.Colors = append(face.Colors, st.Color)
+		}
+
+		faces = append(faces, face)
+	}
+
+	// Порядок художника: дальние стороны рисуются первыми
+	sort.SliceStable(faces, func(i, j int) bool { return faces[i].Depth > faces[j].Depth })
+
+	// Считаем размер рамки (viewBox) по контурам видимых сторон
+	margin := perspectiveBorder*perspectiveScale + 3
+	minP := Point{X: math.Inf(1), Y: math.Inf(1)}
+	maxP := Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, face := range faces {
+		for _, p := range face.Outline {
+			minP = Point{X: math.Min(minP.X, p.X), Y: math.Min(minP.Y, p.Y)}
+			maxP = Point{X: math.Max(maxP.X, p.X), Y: math.Max(maxP.Y, p.Y)}
+		}
+	}
+	offset := Point{X: margin - minP.X, Y: margin - minP.Y}
+	viewBoxSize := Point{X: maxP.X - minP.X + 2*margin, Y: maxP.Y - minP.Y + 2*margin}
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, cube.Rotate)
+
+	// Сдвигаем сцену в рамку
+	builder.WriteString(fmt.Sprintf("\r\n<g transform=\"translate(%.2f %.2f)\">", offset.X, offset.Y))
+
+	// Создаём основу (base) из видимых сторон
+	colorBase := colorMapRGBA[cube.Base]
+	builder.WriteString("\r\n\t<g id=\"base\">")
+	for _, face := range faces {
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"base-%s\" d=\"%s\" style=\"fill: %s; stroke: %s; stroke-width: %.2f; stroke-linejoin: round\"/>",
+			face.Side, polygonPath(face.Outline), colorBase, colorBase, 2*perspectiveBorder*perspectiveScale))
+	}
+	builder.WriteString("\r\n\t</g>")
+
+	// Создаём стороны (side)
+	for _, face := range faces {
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", face.Side))
+		for i, d := range face.Stickers {
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"%s\" style=\"fill: %s\"/>", d, colorMapRGBA[face.Colors[i]]))
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	builder.WriteString("\r\n</g>")
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, cube.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// cameraView переводит точки сцены в координаты картинки
+type cameraView struct {
+	Rotation [3]Point3 // Строки матрицы поворота сцены
+	Distance float64   // Расстояние от центра кубика до камеры
+}
+
+// newCameraView строит матрицу поворота сцены: сначала yaw, затем pitch, затем roll
+func newCameraView(camera Camera, distance float64) cameraView {
+	rad := math.Pi / 180
+	yaw, pitch, roll := -camera.Yaw*rad, camera.Pitch*rad, -camera.Roll*rad
+
+	rotY := [3]Point3{{math.Cos(yaw), 0, math.Sin(yaw)}, {0, 1, 0}, {-math.Sin(yaw), 0, math.Cos(yaw)}}
+	rotX := [3]Point3{{1, 0, 0}, {0, math.Cos(pitch), -math.Sin(pitch)}, {0, math.Sin(pitch), math.Cos(pitch)}}
+	rotZ := [3]Point3{{math.Cos(roll), -math.Sin(roll), 0}, {math.Sin(roll), math.Cos(roll), 0}, {0, 0, 1}}
+
+	return cameraView{Rotation: multiply3(rotZ, multiply3(rotX, rotY)), Distance: distance}
+}
+
+// multiply3 перемножает матрицы 3x3, заданные строками
+func multiply3(a, b [3]Point3) [3]Point3 {
+	var m [3]Point3
+	for i := range a {
+		m[i] = Point3{
+			X: a[i].X*b[0].X + a[i].Y*b[1].X + a[i].Z*b[2].X,
+			Y: a[i].X*b[0].Y + a[i].Y*b[1].Y + a[i].Z*b[2].Y,
+			Z: a[i].X*b[0].Z + a[i].Y*b[1].Z + a[i].Z*b[2].Z,
+		}
+	}
+	return m
+}
+
+// rotate поворачивает точку сцены к камере
+func (v cameraView) rotate(p Point3) Point3 {
+	return Point3{X: v.Rotation[0].dot(p), Y: v.Rotation[1].dot(p), Z: v.Rotation[2].dot(p)}
+}
+
+// project проецирует точку сцены на картинку. Камера смотрит вдоль оси -Z
+func (v cameraView) project(p Point3) Point {
+	r := v.rotate(p)
+	k := perspectiveScale * v.Distance / (v.Distance - r.Z)
+	return Point{X: r.X * k, Y: -r.Y * k}
+}
+
+// depth возвращает расстояние от камеры до точки сцены
+func (v cameraView) depth(p Point3) float64 {
+	r := v.rotate(p)
+	return math.Sqrt(r.X*r.X + r.Y*r.Y + (v.Distance-r.Z)*(v.Distance-r.Z))
+}
+
+// facing проверяет, обращена ли плоскость с точкой p и направлением normal к камере
+func (v cameraView) facing(p, normal Point3) bool {
+	r, n := v.rotate(p), v.rotate(normal)
+	return n.X*-r.X+n.Y*-r.Y+n.Z*(v.Distance-r.Z) > 1e-9
+}
+
+// roundedSquare строит атрибут d наклейки со скруглёнными углами с центром в точке p
+func (v cameraView) roundedSquare(p, u, w Point3) string {
+	corners := [4]Point3{
+		p.add(u.scale(-perspectiveSticker)).add(w.scale(-perspectiveSticker)),
+		p.add(u.scale(perspectiveSticker)).add(w.scale(-perspectiveSticker)),
+		p.add(u.scale(perspectiveSticker)).add(w.scale(perspectiveSticker)),
+		p.add(u.scale(-perspectiveSticker)).add(w.scale(perspectiveSticker)),
+	}
+
+	var d strings.Builder
+	for i, corner := range corners {
+		prev, next := corners[(i+3)%4], corners[(i+1)%4]
+		from := v.project(corner.add(prev.sub(corner).scale(perspectiveRound / (2 * perspectiveSticker))))
+		to := v.project(corner.add(next.sub(corner).scale(perspectiveRound / (2 * perspectiveSticker))))
+		c := v.project(corner)
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		d.WriteString(fmt.Sprintf("%s%.2f %.2fQ%.2f %.2f %.2f %.2f", command, from.X, from.Y, c.X, c.Y, to.X, to.Y))
+	}
+	d.WriteString("z")
+	return d.String()
+}
+
+// faceTangents возвращает два направления вдоль стороны с нормалью n
+func faceTangents(n Vec3) (Point3, Point3) {
+	switch {
+	case n.X != 0:
+		return Point3{Z: 1}, Point3{Y: 1}
+	case n.Y != 0:
+		return Point3{X: 1}, Point3{Z: 1}
+	default:
+		return Point3{X: 1}, Point3{Y: 1}
+	}
+}
+
+// polygonPath строит атрибут d замкнутого многоугольника
+func polygonPath(points []Point) string {
+	var d strings.Builder
+	for i, p := range points {
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		d.WriteString(fmt.Sprintf("%s%.2f %.2f", command, p.X, p.Y))
+	}
+	d.WriteString("z")
+	return d.String()
+}
+
+func (a Point3) add(b Point3) Point3    { return Point3{X: a.X + b.X, Y: a.Y + b.Y, Z: a.Z + b.Z} }
+func (a Point3) sub(b Point3) Point3    { return Point3{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z} }
+func (a Point3) scale(k float64) Point3 { return Point3{X: a.X * k, Y: a.Y * k, Z: a.Z * k} }
+func (a Point3) dot(b Point3) float64   { return a.X*b.X + a.Y*b.Y + a.Z*b.Z }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StageMask определяет, остаётся ли наклейка цветной на стадии сборки.
+// p — центр кубика, n — направление наклейки (в текущем положении кубика)
+type StageMask func(s *CubeState, p, n Vec3) bool
+
+// Цвет, которым закрашиваются скрытые стадией наклейки
+const stageMaskColor = 'X'
+
+// Предопределённые стадии сборки (CFOP, Roux, ZZ и блоки Petrus).
+// Последний слой (LL) всегда сверху, первый слой — снизу
+var stageMasks = map[string]StageMask{
+	// CFOP
+	"fl":    func(s *CubeState, p, n Vec3) bool { return s.isCenter(p) || s.isBottom(p) },
+	"cross": crossMask,
+	"f2l":   func(s *CubeState, p, n Vec3) bool { return s.isCenter(p) || !s.isTop(p) },
+	"f2l_1": f2lSlots(1),
+	"f2l_2": f2lSlots(2),
+	"f2l_3": f2lSlots(3),
+	"f2l_4": f2lSlots(4),
+	"oll": func(s *CubeState, p, n Vec3) bool {
+		return !s.isTop(p) || n == sideNormals[Up]
+	},
+	"ocll": func(s *CubeState, p, n Vec3) bool {
+		return !s.isTop(p) || (n == sideNormals[Up] && !s.isEdge(p))
+	},
+	"oell": func(s *CubeState, p, n Vec3) bool {
+		return !s.isTop(p) || (n == sideNormals[Up] && !s.isCorner(p))
+	},
+	"cll": func(s *CubeState, p, n Vec3) bool {
+		return !s.isTop(p) || !s.isEdge(p)
+	},
+	"coll": func(s *CubeState, p, n Vec3) bool {
+		return !s.isTop(p) || !s.isEdge(p) || n == sideNormals[Up]
+	},
+	"ell": func(s *CubeState, p, n Vec3) bool {
+		return !s.isTop(p) || !s.isCorner(p)
+	},
+	"pll":  func(s *CubeState, p, n Vec3) bool { return true },
+	"zbll": func(s *CubeState, p, n Vec3) bool { return true },
+
+	// Roux
+	"roux_fb": func(s *CubeState, p, n Vec3) bool { return s.isLeftBlock(p) },
+	"roux_sb": func(s *CubeState, p, n Vec3) bool { return s.isLeftBlock(p) || s.isRightBlock(p) },
+	"cmll": func(s *CubeState, p, n Vec3) bool {
+		return s.isLeftBlock(p) || s.isRightBlock(p) || (s.isTop(p) && s.isCorner(p))
+	},
+
+	// ZZ
+	"eo": func(s *CubeState, p, n Vec3) bool { return s.isCenter(p) || s.isEOSticker(p, n) },
+	"eoline": func(s *CubeState, p, n Vec3) bool {
+		return s.isCenter(p) || s.isEOSticker(p, n) || s.isLineEdge(p)
+	},
+
+	// Блоки Petrus (только для кубика 3x3x3, см. stageCubeSizes)
+	"2x2x2": func(s *CubeState, p, n Vec3) bool { return p.X <= 0 && p.Y <= 0 && p.Z <= 0 },
+	"2x2x3": func(s *CubeState, p, n Vec3) bool { return p.X <= 0 && p.Y <= 0 },
+}
+
+// Стадии, которые есть только у кубика одного размера
+var stageCubeSizes = map[string]Size{
+	"2x2x2": {X: 3, Y: 3, Z: 3},
+	"2x2x3": {X: 3, Y: 3, Z: 3},
+}
+
+// Псевдонимы стадий
+func init() {
+	stageMasks["f2b"] = stageMasks["roux_sb"]
+	stageMasks["ll"] = stageMasks["pll"]
+}
+
+// crossMask оставляет цветными центры и крест на нижней стороне
+func crossMask(s *CubeState, p, n Vec3) bool {
+	return s.isCenter(p) || (s.isBottom(p) && !s.isCorner(p))
+}
+
+// f2lSlots возвращает стадию "крест и n собранных слотов" (порядок слотов: FR, FL, BR, BL)
+func f2lSlots(count int) StageMask {
+	slots := []Vec3{{X: 1, Z: 1}, {X: -1, Z: 1}, {X: 1, Z: -1}, {X: -1, Z: -1}}[:count]
+	return func(s *CubeState, p, n Vec3) bool {
+		if crossMask(s, p, n) {
+			return true
+		}
+		if s.isTop(p) {
+			return false
+		}
+		for _, slot := range slots {
+			if p.X*slot.X > 0 && p.Z*slot.Z > 0 {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// outerCount возвращает число координат кубика, лежащих на внешних слоях
+// (1 — центр, 2 — ребро, 3 — угол)
+func (s *CubeState) outerCount(p Vec3) int {
+	count := 0
+	if p.X == s.Size.X-1 || p.X == -s.Size.X+1 {
+		count++
+	}
+	if p.Y == s.Size.Y-1 || p.Y == -s.Size.Y+1 {
+		count++
+	}
+	if p.Z == s.Size.Z-1 || p.Z == -s.Size.Z+1 {
+		count++
+	}
+	return count
+}
+
+func (s *CubeState) isCorner(p Vec3) bool { return s.outerCount(p) == 3 }
+func (s *CubeState) isEdge(p Vec3) bool   { return s.outerCount(p) == 2 }
+func (s *CubeState) isCenter(p Vec3) bool { return s.outerCount(p) <= 1 }
+func (s *CubeState) isTop(p Vec3) bool    { return p.Y == s.Size.Y-1 }
+func (s *CubeState) isBottom(p Vec3) bool { return p.Y == -s.Size.Y+1 }
+
+// isLeftBlock проверяет, входит ли кубик в первый блок Roux (левый блок 1x2x3)
+func (s *CubeState) isLeftBlock(p Vec3) bool {
+	return p.X < 0 && !s.isTop(p)
+}
+
+// isRightBlock проверяет, входит ли кубик во второй блок Roux (правый блок 1x2x3)
+func (s *CubeState) isRightBlock(p Vec3) bool {
+	return p.X > 0 && !s.isTop(p)
+}
+
+// isLineEdge проверяет, входит ли кубик в линию ZZ: рёбра DF и DB (на больших кубиках — все их детали)
+func (s *CubeState) isLineEdge(p Vec3) bool {
+	return s.isBottom(p) && abs(p.X) < s.Size.X-1 && abs(p.Z) == s.Size.Z-1
+}
+
+// isEOSticker проверяет, важна ли наклейка для ориентации рёбер:
+// U/D наклейки рёбер верхнего и нижнего слоёв и F/B наклейки рёбер среднего слоя
+func (s *CubeState) isEOSticker(p, n Vec3) bool {
+	if !s.isEdge(p) {
+		return false
+	}
+	if s.isTop(p) || s.isBottom(p) {
+		return n.Y != 0
+	}
+	return n.Z != 0
+}
+
+// ApplyStage закрашивает серым наклейки, не относящиеся к стадии сборки.
+// Стадия может содержать поворот через дефис, например cross-x2
+func (s *CubeState) ApplyStage(stage string) error {
+	name, rotation, _ := strings.Cut(strings.ToLower(stage), "-")
+	mask, ok := stageMasks[name]
+	if !ok {
+		return fmt.Errorf("unknown stage %q", name)
+	}
+	if size, ok := stageCubeSizes[name]; ok && s.Size != size {
+		return fmt.Errorf("stage %q is supported only on the %dx%dx%d cube", name, size.X, size.Y, size.Z)
+	}
+
+	moves, err := ParseRotation(rotation, s.Size)
+	if err != nil {
+		return err
+	}
+
+	// Маска поворачивается вместе с кубиком, поэтому наклейки проверяются
+	// в положении, повёрнутом обратно
+	probe := &CubeState{Size: s.Size, Stickers: append([]Sticker(nil), s.Stickers...)}
+	if err := probe.ApplyMoves(InvertAlgorithm(moves)); err != nil {
+		return err
+	}
+
+	for i, st := range probe.Stickers {
+		if !mask(probe, st.Pos, st.Normal) {
+			s.Stickers[i].Color = stageMaskColor
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import "testing"
+
+// faceString возвращает цвета стороны строками развёртки через /
+func faceString(faces map[Side][][]rune, side Side) string {
+	var s []rune
+	for r, row := range faces[side] {
+		if r > 0 {
+			s = append(s, '/')
+		}
+		s = append(s, row...)
+	}
+	return string(s)
+}
+
+func TestApplyStage(t *testing.T) {
+	tests := []struct {
+		stage string
+		faces map[Side]string
+	}{
+		{"cross", map[Side]string{Up: "XXX/XWX/XXX", Down: "XYX/YYY/XYX", Front: "XXX/XGX/XGX"}},
+		{"fl", map[Side]string{Up: "XXX/XWX/XXX", Down: "YYY/YYY/YYY", Front: "XXX/XGX/GGG"}},
+		{"f2l", map[Side]string{Up: "XXX/XWX/XXX", Front: "XXX/GGG/GGG"}},
+		{"f2l_1", map[Side]string{Front: "XXX/XGG/XGG", Right: "XXX/RRX/RRX"}},
+		{"oll", map[Side]string{Up: "WWW/WWW/WWW", Front: "XXX/GGG/GGG"}},
+		{"ocll", map[Side]string{Up: "WXW/XWX/WXW", Front: "XXX/GGG/GGG"}},
+		{"pll", map[Side]string{Up: "WWW/WWW/WWW", Front: "GGG/GGG/GGG"}},
+		{"roux_fb", map[Side]string{Left: "XXX/OOO/OOO", Right: "XXX/XXX/XXX", Front: "XXX/GXX/GXX"}},
+		{"eo", map[Side]string{Up: "XWX/WWW/XWX", Front: "XXX/GGG/XXX"}},
+		{"eoline", map[Side]string{Up: "XWX/WWW/XWX", Front: "XXX/GGG/XGX", Down: "XYX/YYY/XYX"}},
+		{"2x2x2", map[Side]string{Left: "XXX/OOX/OOX", Down: "XXX/YYX/YYX"}},
+		{"cross-x2", map[Side]string{Up: "XWX/WWW/XWX", Down: "XXX/XYX/XXX"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stage, func(t *testing.T) {
+			state := solvedState(t, "3x3x3")
+			if err := state.ApplyStage(tt.stage); err != nil {
+				t.Fatalf("ApplyStage(%q): %v", tt.stage, err)
+			}
+			faces := state.Faces()
+			for side, want := range tt.faces {
+				if got := faceString(faces, side); got != want {
+					t.Errorf("%s = %s, want %s", side, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyStageMovesWithStickers(t *testing.T) {
+	// Маска накладывается на собранный кубик, а затем движется вместе с наклейками
+	state := solvedState(t, "3x3x3")
+	if err := state.ApplyStage("f2l"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.ApplyAlgorithm("R"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := faceString(state.Faces(), Up), "XXX/XWG/XXG"; got != want {
+		t.Errorf("up = %s, want %s", got, want)
+	}
+}
+
+func TestApplyStageOnOtherSizes(t *testing.T) {
+	// Линия ZZ на больших кубиках — все детали рёбер DF и DB
+	tests := []struct {
+		dimensions string
+		side       Side
+		want       string
+	}{
+		{"4x4x4", Front, "XXXX/GGGG/GGGG/XGGX"},
+		{"4x4x4", Back, "XXXX/BBBB/BBBB/XBBX"},
+		{"5x5x5", Front, "XXXXX/GGGGG/GGGGG/GGGGG/XGGGX"},
+	}
+
+	for _, tt := range tests {
+		state := solvedState(t, tt.dimensions)
+		if err := state.ApplyStage("eoline"); err != nil {
+			t.Fatalf("%s: %v", tt.dimensions, err)
+		}
+		if got := faceString(state.Faces(), tt.side); got != tt.want {
+			t.Errorf("%s eoline %s = %s, want %s", tt.dimensions, tt.side, got, tt.want)
+		}
+	}
+}
+
+func TestApplyStageRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		dimensions string
+		stage      string
+	}{
+		{"3x3x3", "f3l"},
+		{"3x3x3", "cross-R"},
+		{"3x3x3", "oll-q"},
+		// Блоки Petrus определены только для 3x3x3
+		{"4x4x4", "2x2x2"},
+		{"2x2x2", "2x2x2"},
+		{"3x3x4", "2x2x3"},
+	}
+
+	for _, tt := range tests {
+		state := solvedState(t, tt.dimensions)
+		if err := state.ApplyStage(tt.stage); err == nil {
+			t.Errorf("%s ApplyStage(%q) returned no error", tt.dimensions, tt.stage)
+		}
+	}
+}
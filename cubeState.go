@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Vec3 точка или вектор в пространстве кубика.
+// Координаты удвоены, чтобы центры кубиков лежали в целых числах:
+// для кубоида XxYxZ центры по оси X принимают значения -X+1, -X+3, ..., X-1
+type Vec3 struct {
+	X, Y, Z int
+}
+
+// Sticker хранит одну наклейку кубика
+type Sticker struct {
+	Pos          Vec3 // Центр кубика, на котором находится наклейка
+	Normal       Vec3 // Направление, в которое смотрит наклейка
+	Color        rune // Цвет наклейки
+	Origin       Vec3 // Центр кубика в собранном состоянии
+	OriginNormal Vec3 // Направление наклейки в собранном состоянии
+	Block        int  // Номер склеенного блока, в который входит деталь (0 — деталь не склеена)
+	Up           Vec3 // Направление верхнего края наклейки (для суперкубов и кубиков с картинками)
+}
+
+// CubeState хранит полное состояние наклеек кубоида XxYxZ
+type CubeState struct {
+	Size     Size      // Размер Кубика Рубика XYZ
+	Stickers []Sticker // Все наклейки всех шести сторон
+	Base     rune      // Цвет основы (base)
+}
+
+// Порядок сторон в строке цветов (как в развёртке)
+var stateSides = [...]Side{Front, Left, Up, Right, Down, Back}
+
+// Цвета сторон собранного кубика по умолчанию (белый сверху, зелёный спереди)
+var defaultSchemeColors = map[Side]string{
+	Front: "G",
+	Left:  "O",
+	Up:    "W",
+	Right: "R",
+	Down:  "Y",
+	Back:  "B",
+}
+
+// Нормали сторон кубика: X вправо, Y вверх, Z на зрителя
+var sideNormals = map[Side]Vec3{
+	Front: {Z: 1},
+	Back:  {Z: -1},
+	Right: {X: 1},
+	Left:  {X: -1},
+	Up:    {Y: 1},
+	Down:  {Y: -1},
+}
+
+// Направления верхнего края клеток сторон в развёртке: верх верхней стороны смотрит назад,
+// верх нижней — вперёд, у боковых сторон — вверх
+var sideUps = map[Side]Vec3{
+	Front: {Y: 1},
+	Back:  {Y: 1},
+	Right: {Y: 1},
+	Left:  {Y: 1},
+	Up:    {Z: -1},
+	Down:  {Z: 1},
+}
+
+// NewCubeState создаёт состояние кубика из сеток цветов сторон (в раскладке развёртки)
+func NewCubeState(size Size, colors map[Side][][]rune, base rune) *CubeState {
+	state := &CubeState{Size: size, Base: base}
+	for _, side := range stateSides {
+		grid := colors[side]
+		for r := range grid {
+			for c := range grid[r] {
+				pos := state.stickerPos(side, r, c)
+				state.Stickers = append(state.Stickers, Sticker{
+					Pos:          pos,
+					Normal:       sideNormals[side],
+					Color:        grid[r][c],
+					Origin:       pos,
+					OriginNormal: sideNormals[side],
+					Up:           sideUps[side],
+				})
+			}
+		}
+	}
+	return state
+}
+
+// ParseCubeStateParams парсит размеры и цвета собранного кубика для построения состояния.
+// Цвета указываются в порядке развёртки: {front}-{left}-{up}-{right}-{down}-{back}-{base}.
+// Для flat можно указать две размерности {x}x{z}, тогда высота кубика берётся равной x
+func ParseCubeStateParams(pDimensions, pColors string) (*CubeState, error) {
+
+	// Извлечение размеров из строки pDimensions
+	dimensions := strings.Split(pDimensions, "x")
+	if len(dimensions) == 2 {
+		dimensions = []string{dimensions[0], dimensions[0], dimensions[1]}
+	}
+	if len(dimensions) != 3 {
+		return nil, fmt.Errorf("invalid dimensions: expected 3 dimensions")
+	}
+
+	dX, err1 := strconv.Atoi(dimensions[0])
+	dY, err2 := strconv.Atoi(dimensions[1])
+	dZ, err3 := strconv.Atoi(dimensions[2])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, fmt.Errorf("invalid dimension values, expected integer values")
+	}
+	if dX < 1 || dY < 1 || dZ < 1 || dX > 64 || dY > 64 || dZ > 64 {
+		return nil, fmt.Errorf("dimension values must be between 1 and 64")
+	}
+
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrDefault := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	size := Size{X: dX, Y: dY, Z: dZ}
+	colors := make(map[Side][][]rune)
+	for i, side := range stateSides {
+		w, h := size.faceDims(side)
+		colors[side] = stringToRuneGrid(getColorOrDefault(i, defaultSchemeColors[side]), w, h)
+	}
+	base := stringToRuneGrid(getColorOrDefault(6, "K"), 1, 1)[0][0]
+
+	return NewCubeState(size, colors, base), nil
+}
+
+// faceDims возвращает ширину и высоту стороны в наклейках (в раскладке развёртки)
+func (s Size) faceDims(side Side) (int, int) {
+	switch side {
+	case Up, Down:
+		return s.X, s.Z
+	case Left, Right:
+		return s.Z, s.Y
+	default:
+		return s.X, s.Y
+	}
+}
+
+// stickerPos возвращает центр кубика для наклейки в строке r и столбце c стороны side
+func (s *CubeState) stickerPos(side Side, r, c int) Vec3 {
+	X, Y, Z := s.Size.X, s.Size.Y, s.Size.Z
+	switch side {
+	case Up:
+		return Vec3{X: 2*c - X + 1, Y: Y - 1, Z: 2*r - Z + 1}
+	case Down:
+		return Vec3{X: 2*c - X + 1, Y: -Y + 1, Z: Z - 1 - 2*r}
+	case Front:
+		return Vec3{X: 2*c - X + 1, Y: Y - 1 - 2*r, Z: Z - 1}
+	case Back:
+		return Vec3{X: X - 1 - 2*c, Y: Y - 1 - 2*r, Z: -Z + 1}
+	case Right:
+		return Vec3{X: X - 1, Y: Y - 1 - 2*r, Z: Z - 1 - 2*c}
+	default: // Left
+		return Vec3{X: -X + 1, Y: Y - 1 - 2*r, Z: 2*c - Z + 1}
+	}
+}
+
+// stickerIndex возвращает сторону, строку и столбец наклейки по её положению
+func (s *CubeState) stickerIndex(pos, normal Vec3) (Side, int, int) {
+	X, Y, Z := s.Size.X, s.Size.Y, s.Size.Z
+	switch normal {
+	case sideNormals[Up]:
+		return Up, (pos.Z + Z - 1) / 2, (pos.X + X - 1) / 2
+	case sideNormals[Down]:
+		return Down, (Z - 1 - pos.Z) / 2, (pos.X + X - 1) / 2
+	case sideNormals[Front]:
+		return Front, (Y - 1 - pos.Y) / 2, (pos.X + X - 1) / 2
+	case sideNormals[Back]:
+		return Back, (Y - 1 - pos.Y) / 2, (X - 1 - pos.X) / 2
+	case sideNormals[Right]:
+		return Right, (Y - 1 - pos.Y) / 2, (Z - 1 - pos.Z) / 2
+	default: // Left
+		return Left, (Y - 1 - pos.Y) / 2, (pos.Z + Z - 1) / 2
+	}
+}
+
+// Faces возвращает сетки цветов всех сторон в раскладке развёртки
+func (s *CubeState) Faces() map[Side][][]rune {
+	faces := make(map[Side][][]rune)
+	for _, side := range stateSides {
+		w, h := s.Size.faceDims(side)
+		faces[side] = make([][]rune, h)
+		for r := range faces[side] {
+			faces[side][r] = make([]rune, w)
+		}
+	}
+	for _, st := range s.Stickers {
+		side, r, c := s.stickerIndex(st.Pos, st.Normal)
+		faces[side][r][c] = st.Color
+	}
+	faces[Base] = [][]rune{{s.Base}}
+	return faces
+}
+
+// Unfolded возвращает развёртку кубика для GenerateUnfoldedCube
+func (s *CubeState) Unfolded() FlatCube {
+	cube := FlatCube{Size: s.Size, Colors: s.Faces()}
+	if s.bandaged() {
+		cube.Blocks = s.blockState().Unfolded().Colors
+	}
+	return cube
+}
+
+// Isometric возвращает изометрический вид кубика для GenerateIsometricCube
+func (s *CubeState) Isometric() IsometricCube {
+	faces := s.Faces()
+
+	// В изометрии верхняя сторона хранится по столбцам: строка — позиция слева направо,
+	// столбец — глубина от передней стороны к задней
+	up := make([][]rune, s.Size.X)
+	for x := range up {
+		up[x] = make([]rune, s.Size.Z)
+		for d := range up[x] {
+			up[x][d] = faces[Up][s.Size.Z-1-d][x]
+		}
+	}
+
+	cube := IsometricCube{
+		Size: s.Size,
+		Colors: map[Side][][]rune{
+			Front: faces[Front],
+			Up:    up,
+			Right: faces[Right],
+			Base:  faces[Base],
+		},
+	}
+	if s.bandaged() {
+		cube.Blocks = s.blockState().Isometric().Colors
+	}
+	return cube
+}
+
+// Flat возвращает вид сверху для GenerateFlatCube: верхняя сторона
+// и верхние ряды соседних сторон вокруг неё
+func (s *CubeState) Flat() FlatCube {
+	faces := s.Faces()
+	X, Z := s.Size.X, s.Size.Z
+
+	up := make([][]rune, 1)
+	down := make([][]rune, 1)
+	up[0] = make([]rune, X)
+	down[0] = make([]rune, X)
+	for x := 0; x < X; x++ {
+		up[0][x] = faces[Back][0][X-1-x]
+		down[0][x] = faces[Front][0][x]
+	}
+
+	left := make([][]rune, Z)
+	right := make([][]rune, Z)
+	for z := 0; z < Z; z++ {
+		left[z] = []rune{faces[Left][0][z]}
+		right[z] = []rune{faces[Right][0][Z-1-z]}
+	}
+
+	cube := FlatCube{
+		Size: Size{X: X, Y: Z},
+		Colors: map[Side][][]rune{
+			Front: faces[Up],
+			Left:  left,
+			Up:    up,
+			Right: right,
+			Down:  down,
+			Base:  faces[Base],
+		},
+	}
+	if s.bandaged() {
+		cube.Blocks = s.blockState().Flat().Colors
+	}
+	return cube
+}
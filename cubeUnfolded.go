@@ -79,33 +79,12 @@ func GenerateUnfoldedCube(cube FlatCube) string {
 
 	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
 
-	cube.SideParams = map[Side]FlatSideParameter{
-		Front: {
-			Base: Point{X: lZ - 7, Y: lZ - 7},
-		},
-		Left: {
-			Base: Point{X: 0, Y: lZ - 7},
-		},
-		Up: {
-			Base: Point{X: lZ - 7, Y: 0},
-		},
-		Right: {
-			Base: Point{X: lZ + lX - 7*2, Y: lZ - 7},
-		},
-		Down: {
-			Base: Point{X: lZ - 7, Y: lZ + lY - 7*2},
-		},
-		Back: {
-			Base: Point{X: lZ*2 + lX - 7*3, Y: lZ - 7},
-		},
-	}
+	cube.SideParams = unfoldedSideParams(cube.Size)
 
 	// // // // // СТРОИМ SVG
 
 	// Основной SVG-код для кубика Рубика
-	mianSVG := fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %d %d\">",
-		int(lZ*2+lX*2-7*3), int(lZ*2+lY-7*2))
-	builder.WriteString(mianSVG)
+	GenerateViewBox(&builder, lZ*2+lX*2-7*3, lZ*2+lY-7*2, cube.Rotate)
 
 	// Генерируем фон
 	colorBase := cube.Colors[Base][0][0]
@@ -123,19 +102,57 @@ func GenerateUnfoldedCube(cube FlatCube) string {
 	GenerateUnfoldedSide(&builder, cube, Down)
 	GenerateUnfoldedSide(&builder, cube, Back)
 
+	// Генерация меток направления наклеек
+	GenerateSupercubeMarkers(&builder, cube.Supercube)
+
 	// Закрытие SVG
-	builder.WriteString("\r\n</svg>")
+	CloseViewBox(&builder, cube.Rotate)
 
 	// Возвращаем финальную строку SVG
 	return builder.String()
 }
 
+// unfoldedSideParams возвращает положения сторон развёртки кубика: стороны длиной 8+n*49
+// раскладываются крестом и перекрываются на 7 точек
+func unfoldedSideParams(size Size) map[Side]FlatSideParameter {
+	lX := 8 + float64(size.X)*49
+	lY := 8 + float64(size.Y)*49
+	lZ := 8 + float64(size.Z)*49
+
+	return map[Side]FlatSideParameter{
+		Front: {
+			Base: Point{X: lZ - 7, Y: lZ - 7},
+		},
+		Left: {
+			Base: Point{X: 0, Y: lZ - 7},
+		},
+		Up: {
+			Base: Point{X: lZ - 7, Y: 0},
+		},
+		Right: {
+			Base: Point{X: lZ + lX - 7*2, Y: lZ - 7},
+		},
+		Down: {
+			Base: Point{X: lZ - 7, Y: lZ + lY - 7*2},
+		},
+		Back: {
+			Base: Point{X: lZ*2 + lX - 7*3, Y: lZ - 7},
+		},
+	}
+}
+
 func GenerateUnfoldedSide(builder *strings.Builder, cube FlatCube, side Side) {
 	startBase := cube.SideParams[side].Base
 
+	// Склеенные элементы рисуются одной наклейкой
+	groups, merged := stickerGroups(cube.Colors[side], cube.Blocks[side])
+
 	builder.WriteString("\r\n\t<g id=\"" + side.String() + "\">")
 	for y := 0; y < len(cube.Colors[side]); y++ {
 		for x := 0; x < len(cube.Colors[side][y]); x++ {
+			if merged[[2]int{y, x}] {
+				continue
+			}
 			color := cube.Colors[side][y][x]
 
 			startX := int(startBase.X) + 7 + x*49
@@ -146,7 +163,37 @@ func GenerateUnfoldedSide(builder *strings.Builder, cube FlatCube, side Side) {
 			builder.WriteString(path)
 		}
 	}
+	for _, group := range groups {
+		y, x := group[0][0], group[0][1]
+		point := func(p Point) Point { return Point{X: startBase.X + 4 + p.X*49, Y: startBase.Y + 4 + p.Y*49} }
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%c-%dx%d\" d=\"%s\" style=\"fill: %s\"/>",
+			side.String()[0], x+1, y+1, mergedStickerPath(group, point, 3, 6.21), colorMapRGBA[cube.Colors[side][y][x]]))
+	}
 
 	// Закрытие группы
 	builder.WriteString("\r\n\t</g>")
 }
+
+// unfoldedCubeLayout раскладывает шесть квадратных сторон длиной l (вместе с основой) крестом,
+// как GenerateUnfoldedCube: соседние стороны перекрываются на 7 точек. Возвращает левые верхние
+// углы сторон, размер рамки (viewBox) и контур основы
+func unfoldedCubeLayout(l float64) (map[Side]Point, Point, []Point) {
+	origins := map[Side]Point{
+		Front: {X: l - 7, Y: l - 7},
+		Left:  {X: 0, Y: l - 7},
+		Up:    {X: l - 7, Y: 0},
+		Right: {X: 2*l - 14, Y: l - 7},
+		Down:  {X: l - 7, Y: 2*l - 14},
+		Back:  {X: 3*l - 21, Y: l - 7},
+	}
+
+	// Контур основы: крест из шести сторон
+	width, height := 4*l-21, 3*l-14
+	outline := []Point{
+		{X: l - 7, Y: 0}, {X: 2*l - 7, Y: 0}, {X: 2*l - 7, Y: l - 7}, {X: width, Y: l - 7},
+		{X: width, Y: 2*l - 7}, {X: 2*l - 7, Y: 2*l - 7}, {X: 2*l - 7, Y: height}, {X: l - 7, Y: height},
+		{X: l - 7, Y: 2*l - 7}, {X: 0, Y: 2*l - 7}, {X: 0, Y: l - 7}, {X: l - 7, Y: l - 7},
+	}
+
+	return origins, Point{X: width, Y: height}, outline
+}
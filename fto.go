@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type FTO struct {
+	Colors map[string][]rune // Карта для хранения цветов каждой стороны
+	Base   rune              // Цвет основы (base)
+	Rotate float64           // Угол поворота картинки в градусах
+}
+
+// Порядок сторон в строке цветов: четыре передние стороны, затем четыре задние
+var ftoFaces = [...]string{"U", "F", "R", "L", "B", "D", "BR", "BL"}
+
+// Параметры построения FTO
+const (
+	ftoOrder = 3 // Число рядов наклеек на стороне
+	ftoGap   = 3 // Расстояние между передней и задней половинами развёртки (в толщинах обводки)
+)
+
+// ParseFTOParams парсит параметры для SVG картинки FTO.
+// Цвета указываются в порядке {U}-{F}-{R}-{L}-{B}-{D}-{BR}-{BL}-{base}
+func ParseFTOParams(pColors string) (FTO, error) {
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrDefault := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	// Инициализация структуры FTO
+	fto := FTO{
+		Colors: make(map[string][]rune),
+	}
+
+	// Парсинг цветов для каждой стороны (девять наклеек)
+	for i, face := range ftoFaces {
+		fto.Colors[face] = stringToRuneGrid(getColorOrDefault(i, ftoSchemeColors[face]), ftoOrder*ftoOrder, 1)[0]
+	}
+
+	// Цвет фона (base) будет последним в массиве Colors
+	fto.Base = stringToRuneGrid(getColorOrDefault(len(ftoFaces), "K"), 1, 1)[0][0]
+
+	return fto, nil
+}
+
+// ftoModel возвращает стороны правильного октаэдра, повёрнутого вершиной к зрителю (ось Z):
+// спереди U сверху, F снизу, R справа и L слева, сзади — противоположные им D, B, BL и BR.
+// Вершина Apex каждой стороны — передняя или задняя вершина октаэдра, Left и Right идут так,
+// как их видно снаружи, если повернуть сторону вершиной Apex вверх
+func ftoModel() map[string]pyraminxFace {
+	r := float64(ftoOrder*pyraminxStep) / 2
+
+	front := Point3{Z: r * math.Sqrt2}
+	back := Point3{Z: -r * math.Sqrt2}
+	upLeft := Point3{X: -r, Y: r}
+	upRight := Point3{X: r, Y: r}
+	downLeft := Point3{X: -r, Y: -r}
+	downRight := Point3{X: r, Y: -r}
+
+	return map[string]pyraminxFace{
+		"U":  {Apex: front, Left: upRight, Right: upLeft},
+		"F":  {Apex: front, Left: downLeft, Right: downRight},
+		"R":  {Apex: front, Left: downRight, Right: upRight},
+		"L":  {Apex: front, Left: upLeft, Right: downLeft},
+		"B":  {Apex: back, Left: upLeft, Right: upRight},
+		"D":  {Apex: back, Left: downRight, Right: downLeft},
+		"BR": {Apex: back, Left: upRight, Right: downRight},
+		"BL": {Apex: back, Left: downLeft, Right: upLeft},
+	}
+}
+
+// ftoDrawnFace сторона FTO, спроецированная на картинку
+type ftoDrawnFace struct {
+	Face     string
+	Outline  [3]Point   // Контур стороны
+	Stickers [][3]Point // Треугольники наклеек
+}
+
+// projectFTO проецирует стороны faces на картинку: передние — как их видно спереди,
+// задние — как их видно сзади (зеркально по X), со сдвигом offset
+func projectFTO(faces []string, back bool, offset Point) []ftoDrawnFace {
+	project := func(p Point3) Point {
+		x := p.X
+		if back {
+			x = -x
+		}
+		return Point{X: offset.X + x, Y: offset.Y - p.Y}
+	}
+
+	model := ftoModel()
+	var drawn []ftoDrawnFace
+	for _, name := range faces {
+		face := model[name]
+		d := ftoDrawnFace{Face: name, Outline: [3]Point{project(face.Apex), project(face.Left), project(face.Right)}}
+		for _, t := range face.triangles(ftoOrder) {
+			d.Stickers = append(d.Stickers, [3]Point{project(t[0]), project(t[1]), project(t[2])})
+		}
+		drawn = append(drawn, d)
+	}
+	return drawn
+}
+
+// GenerateIsometricFTO генерирует SVG картинку FTO, повёрнутого вершиной к зрителю (стороны U, F, R и L)
+func GenerateIsometricFTO(fto FTO) string {
+	return generateFTO(fto, projectFTO(ftoFaces[:4], false, Point{}))
+}
+
+// GenerateNetFTO генерирует SVG развёртку FTO: слева передняя половина, справа задняя,
+// как её видно сзади
+func GenerateNetFTO(fto FTO) string {
+	width := float64(ftoOrder * pyraminxStep)
+	faces := projectFTO(ftoFaces[:4], false, Point{})
+	faces = append(faces, projectFTO(ftoFaces[4:], true, Point{X: width + ftoGap*pyraminxBorder})...)
+	return generateFTO(fto, faces)
+}
+
+// generateFTO строит SVG из спроецированных сторон
+func generateFTO(fto FTO, faces []ftoDrawnFace) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Считаем размер рамки (viewBox) по контурам сторон
+	var outlines [][]Point
+	for _, face := range faces {
+		outlines = append(outlines, face.Outline[:])
+	}
+	offset, viewBoxSize := fitOutlines(outlines, float64(pyraminxBorder+3))
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, fto.Rotate)
+
+	// Сдвигаем сцену в рамку
+	builder.WriteString(fmt.Sprintf("\r\n<g transform=\"translate(%.2f %.2f)\">", offset.X, offset.Y))
+
+	// Создаём основу (base)
+	colorBase := colorMapRGBA[fto.Base]
+	builder.WriteString("\r\n\t<g id=\"base\">")
+	for _, face := range faces {
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"base-%s\" d=\"%s\" style=\"fill: %s; stroke: %s; stroke-width: %d; stroke-linejoin: round\"/>",
+			strings.ToLower(face.Face), polygonPath(face.Outline[:]), colorBase, colorBase, 2*pyraminxBorder))
+	}
+	builder.WriteString("\r\n\t</g>")
+
+	// Создаём стороны (side)
+	for _, face := range faces {
+		id := strings.ToLower(face.Face)
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", id))
+		for i, t := range face.Stickers {
+			color := fto.Colors[face.Face][i]
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%s-%d\" d=\"%s\" style=\"fill: %s\"/>",
+				id, i+1, roundedTriangle(t), colorMapRGBA[color]))
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	builder.WriteString("\r\n</g>")
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, fto.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
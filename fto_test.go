@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestParseFTOParamsDefaultColors(t *testing.T) {
+	fto, err := ParseFTOParams("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Все стороны собранного FTO разного цвета и не совпадают с серым цветом маски
+	seen := map[rune]string{'X': "mask"}
+	for _, face := range ftoFaces {
+		color := fto.Colors[face][0]
+		if other, ok := seen[color]; ok {
+			t.Errorf("face %s has the same color %c as %s", face, color, other)
+		}
+		seen[color] = face
+	}
+}
+
+func TestParseFTOParamsColors(t *testing.T) {
+	fto, err := ParseFTOParams("WWWWWWWWG-X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(fto.Colors["U"]); got != "WWWWWWWWG" {
+		t.Errorf("U = %s, want WWWWWWWWG", got)
+	}
+	if got := string(fto.Colors["F"]); got != "XXXXXXXXX" {
+		t.Errorf("F = %s, want XXXXXXXXX", got)
+	}
+	if got := string(fto.Colors["BL"]); got != "IIIIIIIII" {
+		t.Errorf("BL = %s, want IIIIIIIII", got)
+	}
+	if fto.Base != 'K' {
+		t.Errorf("base = %c, want K", fto.Base)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Форматы выходной картинки
+const (
+	FormatSVG  = "svg"
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+)
+
+// Размер растровой картинки по умолчанию и максимальный (по большей стороне, в пикселях)
+const (
+	defaultRasterSize = 512
+	maxRasterSize     = 4096
+)
+
+// ParseImageOptions парсит общие параметры картинки всех головоломок: формат по последнему
+// непустому сегменту пути из segments (расширение из него удаляется) и угол поворота rotate
+func ParseImageOptions(c *gin.Context, segments ...*string) (string, float64, error) {
+	segment := segments[0]
+	for _, s := range segments[1:] {
+		if *s != "" {
+			segment = s
+		}
+	}
+	format := ParseImageFormat(c, segment)
+
+	rotate, err := ParseRotate(c.Query("rotate"))
+	if err != nil {
+		return "", 0, err
+	}
+	return format, rotate, nil
+}
+
+// ParseImageFormat определяет формат картинки по расширению последнего сегмента пути
+// (.svg, .png, .jpg, .jpeg) или по заголовку Accept. Расширение удаляется из сегмента
+func ParseImageFormat(c *gin.Context, segment *string) string {
+	extensions := map[string]string{".svg": FormatSVG, ".png": FormatPNG, ".jpg": FormatJPEG, ".jpeg": FormatJPEG}
+	for ext, format := range extensions {
+		if strings.HasSuffix(strings.ToLower(*segment), ext) {
+			*segment = (*segment)[:len(*segment)-len(ext)]
+			return format
+		}
+	}
+
+	// Выбираем первый поддерживаемый тип из заголовка Accept
+	for _, mediaType := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, _ = strings.Cut(strings.TrimSpace(mediaType), ";")
+		switch mediaType {
+		case "image/svg+xml":
+			return FormatSVG
+		case "image/png":
+			return FormatPNG
+		case "image/jpeg":
+			return FormatJPEG
+		}
+	}
+
+	return FormatSVG
+}
+
+// WriteImage отправляет картинку в запрошенном формате.
+// Размер растровой картинки задаётся параметром size
+func WriteImage(c *gin.Context, svg, format string) {
+	if format == FormatSVG {
+		// Установка заголовков и вывод SVG
+		c.Header("Content-Type", "image/svg+xml")
+		c.String(http.StatusOK, svg)
+		return
+	}
+
+	size := defaultRasterSize
+	if pSize := c.Query("size"); pSize != "" {
+		var err error
+		size, err = strconv.Atoi(pSize)
+		if err != nil || size < 1 || size > maxRasterSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("size must be between 1 and %d", maxRasterSize)})
+			return
+		}
+	}
+
+	img, err := RasterizeSVG(svg, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var buffer bytes.Buffer
+	contentType := "image/png"
+	if format == FormatJPEG {
+		// В JPEG нет прозрачности, поэтому картинка кладётся на белый фон
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, image.Point{}, draw.Over)
+		err = jpeg.Encode(&buffer, flat, &jpeg.Options{Quality: 92})
+		contentType = "image/jpeg"
+	} else {
+		err = png.Encode(&buffer, img)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, buffer.Bytes())
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	flags "github.com/jessevdk/go-flags"
@@ -45,8 +48,25 @@ func main() {
 
 	v1 := router.Group("/v1")
 	{
+		v1.GET("/cube/:view/:dimensions", CubeHandler)
 		v1.GET("/cube/:view/:dimensions/:colors", CubeHandler)
+		v1.GET("/skewb/:view/:dimensions", SkewbHandler)
 		v1.GET("/skewb/:view/:dimensions/:colors", SkewbHandler)
+		v1.GET("/pyraminx/:view/:dimensions", PyraminxHandler)
+		v1.GET("/pyraminx/:view/:dimensions/:colors", PyraminxHandler)
+		v1.GET("/megaminx/:view/:dimensions", MegaminxHandler)
+		v1.GET("/megaminx/:view/:dimensions/:colors", MegaminxHandler)
+		v1.GET("/square1/:view/:state", Square1Handler)
+		v1.GET("/square1/:view/:state/:colors", Square1Handler)
+		v1.GET("/clock/:view/:state", ClockHandler)
+		v1.GET("/clock/:view/:state/:colors", ClockHandler)
+		v1.GET("/fto/:view", FTOHandler)
+		v1.GET("/fto/:view/:colors", FTOHandler)
+		for _, puzzle := range cornerCubePuzzles {
+			v1.GET("/"+puzzle+"/:view", CornerCubeHandler(puzzle))
+			v1.GET("/"+puzzle+"/:view/:colors", CornerCubeHandler(puzzle))
+		}
+		v1.GET("/compose/:layout", ComposeHandler(router))
 	}
 
 	// Формирование адреса для прослушивания
@@ -90,54 +110,275 @@ func CubeHandler(c *gin.Context) {
 	pView := c.Param("view")
 	pColors := c.Param("colors")
 
+	// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+	format, rotate, err := ParseImageOptions(c, &pDimensions, &pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Если передан алгоритм, цвета вычисляются по состоянию кубика
+	state, err := ParseCubeStateQuery(c, pDimensions, pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Угол UFR — обычный изометрический вид, состояние кубика для него не нужно
+	pCorner := c.Query("corner")
+	if strings.EqualFold(pCorner, "UFR") {
+		pCorner = ""
+	}
+
+	// Формат цветов зависит от того, строится ли состояние кубика: смешивать их нельзя
+	hasState := state != nil || (pView == "isometric" && pCorner != "")
+	if err := checkCubeColors(pView, pColors, hasState); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	switch pView {
 	case "isometric":
-		// Парсим параметры
-		isometricCube, err := ParseIsometricParams(pDimensions, pColors)
-		if err != nil {
+		// Угол, с которого виден кубик: цвета берутся из развёртки и поворачиваются к зрителю
+		if pCorner != "" {
+			if state == nil {
+				if state, err = ParseCubeStateParams(pDimensions, pColors); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+			}
+			if state, err = state.ViewFromCorner(pCorner); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		// Парсим параметры (или берём цвета из состояния кубика)
+		var isometricCube IsometricCube
+		if state != nil {
+			isometricCube = state.Isometric()
+		} else if isometricCube, err = ParseIsometricParams(pDimensions, pColors); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Толщины слоёв и режим формы (для Mirror Blocks и других головоломок с неравными слоями)
+		if isometricCube.Layers, err = ParseLayerSizes(c.Query("x"), c.Query("y"), c.Query("z"), isometricCube.Size); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if pMono := c.Query("mono"); pMono != "" {
+			if isometricCube.Mono, err = ParseMonoColor(pMono); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		// Добавляем метки направления наклеек
+		if isometricCube.Supercube, err = ResolveSupercube(c.Query("supercube"), state, isometricCube.stickerFrame); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Добавляем индикаторы ориентации
+		if isometricCube.Twists, err = ResolveTwists(c.Query("twists"), state, isometricCube.stickerFrame); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Добавляем стрелки
+		if err := isometricCube.SetArrows(c.Query("arrows")); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		// Генерация SVG
+		isometricCube.Rotate = rotate
 		svg := GenerateIsometricCube(isometricCube)
 
-		// Установка заголовков и вывод SVG
-		c.Header("Content-Type", "image/svg+xml")
-		c.String(http.StatusOK, svg)
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
 		return
 	case "flat":
-		// Парсим параметры
-		flatCube, err := ParseFlatParams(pDimensions, pColors)
-		if err != nil {
+		// Парсим параметры (или берём цвета из состояния кубика)
+		var flatCube FlatCube
+		if state != nil {
+			flatCube = state.Flat()
+		} else if flatCube, err = ParseFlatParams(pDimensions, pColors); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Добавляем метки направления наклеек
+		if flatCube.Supercube, err = ResolveSupercube(c.Query("supercube"), state, flatCube.stickerFrame); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Добавляем индикаторы ориентации
+		if flatCube.Twists, err = ResolveTwists(c.Query("twists"), state, flatCube.stickerFrame); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Добавляем стрелки
+		if err := flatCube.SetArrows(c.Query("arrows"), state); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		// Генерация SVG
+		flatCube.Rotate = rotate
 		svg := GenerateFlatCube(flatCube)
 
-		// Установка заголовков и вывод SVG
-		c.Header("Content-Type", "image/svg+xml")
-		c.String(http.StatusOK, svg)
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
 		return
 	case "unfolded":
-		// Парсим параметры
-		unfoldedCube, err := ParseUnfoldedParams(pDimensions, pColors)
-		if err != nil {
+		// Парсим параметры (или берём цвета из состояния кубика)
+		var unfoldedCube FlatCube
+		if state != nil {
+			unfoldedCube = state.Unfolded()
+		} else if unfoldedCube, err = ParseUnfoldedParams(pDimensions, pColors); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Добавляем метки направления наклеек
+		if unfoldedCube.Supercube, err = ResolveSupercube(c.Query("supercube"), state, unfoldedCube.unfoldedStickerFrame); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		// Генерация SVG
+		unfoldedCube.Rotate = rotate
 		svg := GenerateUnfoldedCube(unfoldedCube)
 
-		// Установка заголовков и вывод SVG
-		c.Header("Content-Type", "image/svg+xml")
-		c.String(http.StatusOK, svg)
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
+		return
+	case "perspective":
+		// Парсим положение камеры
+		camera, err := ParseCameraQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Перспективный вид строится по состоянию кубика (по умолчанию — собранного)
+		if state == nil {
+			if state, err = ParseCubeStateParams(pDimensions, pColors); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		// Генерация SVG
+		perspectiveCube := state.Perspective(camera)
+		perspectiveCube.Rotate = rotate
+		svg := GeneratePerspectiveCube(perspectiveCube)
+
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
 		return
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
 	}
 }
 
+// ParseCubeStateQuery строит состояние кубика по параметрам запроса.
+// Ходы применяются к собранному кубику в порядке: orient, stage (маска), setup, case (обратный алгоритм), alg.
+// Если параметры состояния не переданы, возвращает nil
+func ParseCubeStateQuery(c *gin.Context, pDimensions, pColors string) (*CubeState, error) {
+	orient, hasOrient := c.GetQuery("orient")
+	setup, hasSetup := c.GetQuery("setup")
+	caseAlg, hasCase := c.GetQuery("case")
+	alg, hasAlg := c.GetQuery("alg")
+	stage, hasStage := c.GetQuery("stage")
+	bandage, hasBandage := c.GetQuery("bandage")
+	if !hasOrient && !hasSetup && !hasCase && !hasAlg && !hasStage && !hasBandage {
+		return nil, nil
+	}
+
+	state, err := ParseCubeStateParams(pDimensions, pColors)
+	if err != nil {
+		return nil, err
+	}
+
+	// Поворот кубика перед построением случая
+	orientMoves, err := ParseRotation(orient, state.Size)
+	if err != nil {
+		return nil, err
+	}
+	if err := state.ApplyMoves(orientMoves); err != nil {
+		return nil, err
+	}
+	state.ResetOrigin()
+
+	// Склейки задаются на повёрнутом собранном кубике, ходы не должны разрезать блоки
+	if hasBandage {
+		groups, err := ParseBandage(bandage)
+		if err != nil {
+			return nil, err
+		}
+		if err := state.Bandage(groups); err != nil {
+			return nil, err
+		}
+	}
+
+	// Маска стадии накладывается на собранный кубик и дальше движется вместе с наклейками
+	if hasStage {
+		if err := state.ApplyStage(stage); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := state.ApplyAlgorithm(setup); err != nil {
+		return nil, err
+	}
+	if err := state.ApplyCase(caseAlg); err != nil {
+		return nil, err
+	}
+	if err := state.ApplyAlgorithm(alg); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Виды, которые при состоянии кубика читают цвета в формате unfolded
+var cubeStateColorViews = map[string]bool{"isometric": true, "flat": true}
+
+// checkCubeColors проверяет, что сегмент colors записан в формате, который читает вид.
+// Если строится состояние кубика (alg, case, corner и т.п.), цвета задают развёртку:
+// 6 групп сторон и необязательная седьмая группа основы, как у вида unfolded.
+// Так цвета вида (например, 4 группы изометрии) не читаются молча как развёртка.
+// Без состояния лишние группы, как и раньше, не учитываются
+func checkCubeColors(pView, pColors string, hasState bool) error {
+	if !hasState || pColors == "" || !cubeStateColorViews[pView] {
+		return nil
+	}
+	if count := len(strings.Split(pColors, "-")); count != 6 && count != 7 {
+		return fmt.Errorf("with alg, case, setup, orient, stage, bandage or corner the colors use the unfolded format {front}-{left}-{up}-{right}-{down}-{back}-{base} with 6 or 7 groups, got %d", count)
+	}
+	return nil
+}
+
+// ParseCameraQuery парсит положение камеры для перспективного вида из параметров yaw, pitch, roll и fov
+func ParseCameraQuery(c *gin.Context) (Camera, error) {
+	camera := defaultCamera
+	params := []struct {
+		name  string
+		value *float64
+	}{
+		{"yaw", &camera.Yaw},
+		{"pitch", &camera.Pitch},
+		{"roll", &camera.Roll},
+		{"fov", &camera.FOV},
+	}
+	for _, param := range params {
+		pValue := c.Query(param.name)
+		if pValue == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(pValue, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return Camera{}, fmt.Errorf("invalid %s value %q, expected a number of degrees", param.name, pValue)
+		}
+		*param.value = value
+	}
+	if camera.FOV < 1 || camera.FOV > 120 {
+		return Camera{}, fmt.Errorf("fov must be between 1 and 120 degrees")
+	}
+	return camera, nil
+}
+
 // SkewbHandler обрабатывает запросы для генерации SVG Скьюба
 func SkewbHandler(c *gin.Context) {
 	// Получение параметров из URL
@@ -145,22 +386,383 @@ func SkewbHandler(c *gin.Context) {
 	pView := c.Param("view")
 	pColors := c.Param("colors")
 
+	// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+	format, rotate, err := ParseImageOptions(c, &pDimensions, &pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Если передан алгоритм, цвета вычисляются по состоянию скьюба
+	state, err := ParseSkewbStateQuery(c, pDimensions, pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	switch pView {
 	case "isometric":
-		// Парсим параметры
-		isometricCube, err := ParseIsometricSkewbParams(pDimensions, pColors)
-		if err != nil {
+		// Парсим параметры (или берём цвета из состояния скьюба)
+		var isometricSkewb IsometricSkewb
+		if state != nil {
+			isometricSkewb = state.Isometric()
+		} else if isometricSkewb, err = ParseIsometricSkewbParams(pDimensions, pColors); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		// Генерация SVG
-		svg := GenerateIsometricSkewb(isometricCube)
+		isometricSkewb.Rotate = rotate
+		svg := GenerateIsometricSkewb(isometricSkewb)
+
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
+		return
+	case "flat":
+		// Парсим параметры (или берём цвета из состояния скьюба)
+		var flatSkewb IsometricSkewb
+		if state != nil {
+			flatSkewb = state.Flat()
+		} else if flatSkewb, err = ParseFlatSkewbParams(pDimensions, pColors); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Генерация SVG
+		flatSkewb.Rotate = rotate
+		svg := GenerateFlatSkewb(flatSkewb)
+
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
+		return
+	case "unfolded":
+		// Парсим параметры (или берём цвета из состояния скьюба)
+		var unfoldedSkewb IsometricSkewb
+		if state != nil {
+			unfoldedSkewb = state.Unfolded()
+		} else if unfoldedSkewb, err = ParseUnfoldedSkewbParams(pDimensions, pColors); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// Генерация SVG
+		unfoldedSkewb.Rotate = rotate
+		svg := GenerateUnfoldedSkewb(unfoldedSkewb)
+
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
+	}
+}
+
+// ParseSkewbStateQuery строит состояние скьюба по параметрам запроса.
+// Ходы применяются к собранному скьюбу в порядке: setup, case (обратный алгоритм), alg.
+// Если параметры состояния не переданы, возвращает nil
+func ParseSkewbStateQuery(c *gin.Context, pDimensions, pColors string) (*SkewbState, error) {
+	setup, hasSetup := c.GetQuery("setup")
+	caseAlg, hasCase := c.GetQuery("case")
+	alg, hasAlg := c.GetQuery("alg")
+	if !hasSetup && !hasCase && !hasAlg {
+		return nil, nil
+	}
+
+	state, err := ParseSkewbStateParams(pDimensions, pColors)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := state.ApplyAlgorithm(setup); err != nil {
+		return nil, err
+	}
+	if err := state.ApplyCase(caseAlg); err != nil {
+		return nil, err
+	}
+	if err := state.ApplyAlgorithm(alg); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// PyraminxHandler обрабатывает запросы для генерации SVG Пирамидки
+func PyraminxHandler(c *gin.Context) {
+	// Получение параметров из URL
+	pDimensions := c.Param("dimensions")
+	pView := c.Param("view")
+	pColors := c.Param("colors")
 
-		// Установка заголовков и вывод SVG
-		c.Header("Content-Type", "image/svg+xml")
-		c.String(http.StatusOK, svg)
+	// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+	format, rotate, err := ParseImageOptions(c, &pDimensions, &pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Парсим параметры
+	pyraminx, err := ParsePyraminxParams(pDimensions, pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pyraminx.Rotate = rotate
+
+	// Генерация SVG
+	var svg string
+	switch pView {
+	case "isometric":
+		svg = GenerateIsometricPyraminx(pyraminx)
+	case "flat":
+		svg = GenerateFlatPyraminx(pyraminx)
+	case "net":
+		svg = GenerateNetPyraminx(pyraminx)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
+		return
+	}
+
+	// Вывод картинки в запрошенном формате
+	WriteImage(c, svg, format)
+}
+
+// MegaminxHandler обрабатывает запросы для генерации SVG Мегаминкса (Киломинкса, Гигаминкса и т.д.)
+func MegaminxHandler(c *gin.Context) {
+	// Получение параметров из URL
+	pDimensions := c.Param("dimensions")
+	pView := c.Param("view")
+	pColors := c.Param("colors")
+
+	// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+	format, rotate, err := ParseImageOptions(c, &pDimensions, &pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Парсим параметры
+	megaminx, err := ParseMegaminxParams(pDimensions, pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	megaminx.Rotate = rotate
+
+	// Генерация SVG
+	var svg string
+	switch pView {
+	case "flat":
+		svg = GenerateFlatMegaminx(megaminx)
+	case "unfolded":
+		svg = GenerateUnfoldedMegaminx(megaminx)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
+		return
+	}
+
+	// Вывод картинки в запрошенном формате
+	WriteImage(c, svg, format)
+}
+
+// Square1Handler обрабатывает запросы для генерации SVG Square-1
+func Square1Handler(c *gin.Context) {
+	// Получение параметров из URL
+	pState := c.Param("state")
+	pView := c.Param("view")
+	pColors := c.Param("colors")
+
+	// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+	format, rotate, err := ParseImageOptions(c, &pState, &pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Парсим параметры
+	square1, err := ParseSquare1Params(pState, pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
+	}
+	square1.Rotate = rotate
+
+	// Генерация SVG
+	var svg string
+	switch pView {
+	case "flat":
+		svg = GenerateFlatSquare1(square1)
+	case "isometric":
+		svg = GenerateIsometricSquare1(square1)
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
+		return
+	}
+
+	// Вывод картинки в запрошенном формате
+	WriteImage(c, svg, format)
+}
+
+// ClockHandler обрабатывает запросы для генерации SVG Часов Рубика
+func ClockHandler(c *gin.Context) {
+	// Получение параметров из URL
+	pState := c.Param("state")
+	pView := c.Param("view")
+	pColors := c.Param("colors")
+
+	// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+	format, rotate, err := ParseImageOptions(c, &pState, &pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Парсим параметры
+	clock, err := ParseClockParams(pState, pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	clock.Rotate = rotate
+
+	// Генерация SVG
+	var svg string
+	switch pView {
+	case "front":
+		svg = GenerateClock(clock, false)
+	case "both":
+		svg = GenerateClock(clock, true)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
+		return
+	}
+
+	// Вывод картинки в запрошенном формате
+	WriteImage(c, svg, format)
+}
+
+// FTOHandler обрабатывает запросы для генерации SVG FTO (Face-Turning Octahedron)
+func FTOHandler(c *gin.Context) {
+	// Получение параметров из URL
+	pView := c.Param("view")
+	pColors := c.Param("colors")
+
+	// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+	format, rotate, err := ParseImageOptions(c, &pView, &pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Парсим параметры
+	fto, err := ParseFTOParams(pColors)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fto.Rotate = rotate
+
+	// Генерация SVG
+	var svg string
+	switch pView {
+	case "isometric":
+		svg = GenerateIsometricFTO(fto)
+	case "net":
+		svg = GenerateNetFTO(fto)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
+		return
+	}
+
+	// Вывод картинки в запрошенном формате
+	WriteImage(c, svg, format)
+}
+
+// CornerCubeHandler возвращает обработчик запросов для генерации SVG головоломок на контуре кубика:
+// дино, рекса, реди, айви, хеликоптера или кёрви коптера
+func CornerCubeHandler(puzzle string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Получение параметров из URL
+		pView := c.Param("view")
+		pColors := c.Param("colors")
+
+		// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+		format, rotate, err := ParseImageOptions(c, &pView, &pColors)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Парсим параметры и генерируем SVG
+		var svg string
+		switch pView {
+		case "isometric":
+			cube, err := ParseCornerCubeParams(puzzle, pColors)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			cube.Rotate = rotate
+			svg = GenerateIsometricCornerCube(cube)
+		case "unfolded":
+			cube, err := ParseUnfoldedCornerCubeParams(puzzle, pColors)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			cube.Rotate = rotate
+			svg = GenerateUnfoldedCornerCube(cube)
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown view parameter"})
+			return
+		}
+
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
+	}
+}
+
+// ComposeHandler обрабатывает запросы для сцены из нескольких картинок: каждая картинка
+// строится обработчиками router по своему пути (параметры image) и раскладывается по layout
+func ComposeHandler(router http.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Получение параметров из URL
+		pLayout := c.Param("layout")
+
+		// Формат картинки (по расширению в конце пути или заголовку Accept) и угол поворота
+		format, rotate, err := ParseImageOptions(c, &pLayout)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Парсим раскладку
+		images := c.QueryArray("image")
+		composition, err := ParseComposeParams(pLayout, c.Query("columns"), c.Query("gap"), len(images))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		composition.Rotate = rotate
+
+		// Строим картинки, подписи идут в том же порядке
+		captions := c.QueryArray("caption")
+		if err := CheckComposeCaptions(captions, format); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for i, spec := range images {
+			image, err := RenderComposedImage(router, spec, fmt.Sprintf("image-%d-", i+1))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if i < len(captions) {
+				image.Caption = captions[i]
+			}
+			composition.Images = append(composition.Images, image)
+		}
+
+		// Генерация SVG
+		svg := GenerateComposition(composition)
+
+		// Вывод картинки в запрошенном формате
+		WriteImage(c, svg, format)
 	}
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cubeRouter возвращает маршрутизатор только с запросами кубика
+func cubeRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/cube/:view/:dimensions", CubeHandler)
+	router.GET("/v1/cube/:view/:dimensions/:colors", CubeHandler)
+	return router
+}
+
+func TestCubeHandlerColors(t *testing.T) {
+	tests := []struct {
+		url  string
+		code int
+	}{
+		// Без состояния — формат вида, лишние группы не учитываются
+		{"/v1/cube/isometric/3x3x3/G-W-R", http.StatusOK},
+		{"/v1/cube/isometric/3x3x3/G-W-R-K-Y", http.StatusOK},
+		{"/v1/cube/flat/3x3/Y-R-B-O-G-K-W", http.StatusOK},
+		// Угол UFR — обычный вид
+		{"/v1/cube/isometric/3x3x3/G-W-R?corner=UFR", http.StatusOK},
+		{"/v1/cube/isometric/3x3x3/G-W-R?corner=ufr", http.StatusOK},
+		// С состоянием — развёртка из 6 или 7 групп, как у вида unfolded
+		{"/v1/cube/isometric/3x3x3/G-O-W-R-Y-B?alg=R", http.StatusOK},
+		{"/v1/cube/isometric/3x3x3/G-O-W-R-Y-B-K?alg=R", http.StatusOK},
+		{"/v1/cube/flat/3x3x3/G-O-W-R-Y-B?alg=R", http.StatusOK},
+		{"/v1/cube/unfolded/3x3x3/G-O-W-R-Y-B?alg=R", http.StatusOK},
+		{"/v1/cube/isometric/3x3x3/G-O-W-R-Y-B?corner=DBL", http.StatusOK},
+		{"/v1/cube/isometric/3x3x3?corner=DBL", http.StatusOK},
+		// Формат вида вместе с состоянием — ошибка
+		{"/v1/cube/isometric/3x3x3/G-W-R-K?alg=R", http.StatusBadRequest},
+		{"/v1/cube/isometric/3x3x3/G-W-R?corner=DBL", http.StatusBadRequest},
+		{"/v1/cube/flat/3x3x3/G-O-W-R-Y?alg=R", http.StatusBadRequest},
+	}
+
+	router := cubeRouter()
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if recorder.Code != tt.code {
+			t.Errorf("GET %s = %d, want %d: %s", tt.url, recorder.Code, tt.code, recorder.Body.String())
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Megaminx struct {
+	Order  int               // Порядок: 2 — Киломинкс, 3 — Мегаминкс, 5 — Гигаминкс, 7 — Тераминкс
+	Colors map[string][]rune // Карта для хранения цветов каждой стороны
+	Base   rune              // Цвет основы (base)
+	Rotate float64           // Угол поворота картинки в градусах
+}
+
+// Порядок сторон в строке цветов: верхняя сторона, верхний пояс, нижняя сторона, нижний пояс
+var megaminxFaces = [...]string{"U", "F", "R", "BR", "BL", "L", "D", "DR", "DBR", "B", "DBL", "DL"}
+
+// Соседи каждой стороны через рёбра e0..e4. Вершины стороны V0..V4 идут по часовой стрелке
+// (если смотреть снаружи), ребро ei соединяет Vi и Vi+1. Ребро e2 — опорное: у U оно общее с F,
+// у сторон верхнего пояса — с U, у сторон нижнего пояса — с D, у D — с B
+var megaminxNeighbors = map[string][5]string{
+	"U":   {"BR", "R", "F", "L", "BL"},
+	"F":   {"DL", "L", "U", "R", "DR"},
+	"R":   {"DR", "F", "U", "BR", "DBR"},
+	"BR":  {"DBR", "R", "U", "BL", "B"},
+	"BL":  {"B", "BR", "U", "L", "DBL"},
+	"L":   {"DBL", "BL", "U", "F", "DL"},
+	"D":   {"DR", "DBR", "B", "DBL", "DL"},
+	"DR":  {"R", "DBR", "D", "DL", "F"},
+	"DBR": {"BR", "B", "D", "DR", "R"},
+	"B":   {"BL", "DBL", "D", "DBR", "BR"},
+	"DBL": {"L", "DL", "D", "B", "BL"},
+	"DL":  {"F", "DR", "D", "DBL", "L"},
+}
+
+// Цвета сторон собранного мегаминкса по умолчанию (противоположные стороны — близких оттенков)
+var megaminxSchemeColors = map[string]string{
+	"U":   "W",
+	"F":   "G",
+	"R":   "R",
+	"BR":  "B",
+	"BL":  "Y",
+	"L":   "P",
+	"D":   "X",
+	"DR":  "E",
+	"DBR": "I",
+	"B":   "L",
+	"DBL": "O",
+	"DL":  "C",
+}
+
+// Параметры построения мегаминкса
+const (
+	megaminxStep   = 49 // Длина ребра стороны, приходящаяся на одну наклейку
+	megaminxGap    = 3  // Отступ наклейки от границ её ячейки
+	megaminxRound  = 5  // Радиус скругления углов наклейки
+	megaminxBorder = 4  // Выступ основы за края сторон
+)
+
+// ParseMegaminxParams парсит параметры для SVG картинки мегаминкса.
+// Цвета указываются в порядке {U}-{F}-{R}-{BR}-{BL}-{L}-{D}-{DR}-{DBR}-{B}-{DBL}-{DL}-{base}
+func ParseMegaminxParams(pDimensions, pColors string) (Megaminx, error) {
+
+	// Извлечение порядка из строки pDimensions
+	order, err := strconv.Atoi(pDimensions)
+	if err != nil {
+		return Megaminx{}, fmt.Errorf("invalid dimension values, expected integer values")
+	}
+	if order < 2 || order > 32 {
+		return Megaminx{}, fmt.Errorf("dimension values must be between 2 and 32")
+	}
+
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrDefault := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	// Инициализация структуры Megaminx
+	megaminx := Megaminx{
+		Order:  order,
+		Colors: make(map[string][]rune),
+	}
+
+	// Парсинг цветов для каждой стороны
+	count := megaminxStickerCount(order)
+	for i, face := range megaminxFaces {
+		megaminx.Colors[face] = stringToRuneGrid(getColorOrDefault(i, megaminxSchemeColors[face]), count, 1)[0]
+	}
+
+	// Цвет фона (base) будет последним в массиве Colors
+	megaminx.Base = stringToRuneGrid(getColorOrDefault(len(megaminxFaces), "K"), 1, 1)[0][0]
+
+	return megaminx, nil
+}
+
+// megaminxStickerCount возвращает число наклеек на стороне мегаминкса порядка order
+func megaminxStickerCount(order int) int {
+	k := order / 2
+	if order%2 == 1 {
+		return 5*k*k + 5*k + 1
+	}
+	return 5 * k * k
+}
+
+// megaminxCells делит пятиугольную сторону с вершинами v на ячейки наклеек в порядке строки цветов:
+// центр (у нечётных порядков), затем для каждой вершины Vi угловые ячейки k×k
+// рядами от ребра ei вглубь (в ряду — от вершины вдоль ребра), затем k ячеек ребра ei от края вглубь.
+// У чётных порядков рёбер и центра нет: углы сходятся в центре стороны
+func megaminxCells(v [5]Point, order int) [][]Point {
+	k := order / 2
+	odd := order%2 == 1
+
+	center := Point{}
+	for _, p := range v {
+		center = Point{X: center.X + p.X/5, Y: center.Y + p.Y/5}
+	}
+	lerp := func(a, b Point, t float64) Point {
+		return Point{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}
+	}
+
+	// Угловая область вершины Vi: Vi, A (на ребре ei), Q (внутренняя вершина), B (на ребре ei-1)
+	var a, b, q [5]Point
+	for i := range v {
+		next, prev := v[(i+1)%5], v[(i+4)%5]
+		if odd {
+			a[i] = lerp(v[i], next, float64(k)/float64(order))
+			b[i] = lerp(v[i], prev, float64(k)/float64(order))
+			q[i] = Point{X: a[i].X + b[i].X - v[i].X, Y: a[i].Y + b[i].Y - v[i].Y}
+		} else {
+			a[i] = lerp(v[i], next, 0.5)
+			b[i] = lerp(v[i], prev, 0.5)
+			q[i] = center
+		}
+	}
+
+	var cells [][]Point
+	if odd {
+		cells = append(cells, q[:])
+	}
+	for i := range v {
+		// Билинейная сетка угловой области
+		at := func(u, w float64) Point {
+			return lerp(lerp(v[i], a[i], u), lerp(b[i], q[i], u), w)
+		}
+		for r := 0; r < k; r++ {
+			for c := 0; c < k; c++ {
+				u0, u1 := float64(c)/float64(k), float64(c+1)/float64(k)
+				w0, w1 := float64(r)/float64(k), float64(r+1)/float64(k)
+				cells = append(cells, []Point{at(u0, w0), at(u1, w0), at(u1, w1), at(u0, w1)})
+			}
+		}
+
+		// Полосы рёберной области между углами Vi и Vi+1
+		if odd {
+			j := (i + 1) % 5
+			for r := 0; r < k; r++ {
+				t0, t1 := float64(r)/float64(k), float64(r+1)/float64(k)
+				cells = append(cells, []Point{lerp(a[i], q[i], t0), lerp(b[j], q[j], t0), lerp(b[j], q[j], t1), lerp(a[i], q[i], t1)})
+			}
+		}
+	}
+	return cells
+}
+
+// megaminxEdgeStickers возвращает номера наклеек стороны, прилегающих к ребру e2,
+// в порядке от вершины V2 к вершине V3
+func megaminxEdgeStickers(order int) []int {
+	k := order / 2
+	odd := order%2 == 1
+	first, perCorner := 0, k*k
+	if odd {
+		first, perCorner = 1, k*k+k
+	}
+
+	var indexes []int
+	// Угол V2: первый ряд от вершины вдоль ребра
+	for c := 0; c < k; c++ {
+		indexes = append(indexes, first+2*perCorner+c)
+	}
+	// Ребро e2: крайняя полоса
+	if odd {
+		indexes = append(indexes, first+2*perCorner+k*k)
+	}
+	// Угол V3: первый столбец (прилегает к ребру e2), от середины ребра к вершине
+	for r := k - 1; r >= 0; r-- {
+		indexes = append(indexes, first+3*perCorner+r*k)
+	}
+	return indexes
+}
+
+// megaminxPentagon возвращает вершины правильного пятиугольника с ребром edge,
+// вершиной V0 вверх и центром в точке center
+func megaminxPentagon(center Point, edge float64) [5]Point {
+	radius := edge / (2 * math.Sin(math.Pi/5))
+	var v [5]Point
+	for i := range v {
+		angle := float64(i) * 2 * math.Pi / 5
+		v[i] = Point{X: center.X + radius*math.Sin(angle), Y: center.Y - radius*math.Cos(angle)}
+	}
+	return v
+}
+
+// megaminxNet раскладывает стороны в развёртку из двух «цветков»: U со сторонами верхнего пояса
+// и D со сторонами нижнего пояса, присоединённый к ребру F–DR
+func megaminxNet(edge float64) map[string][5]Point {
+	net := map[string][5]Point{"U": megaminxPentagon(Point{}, edge)}
+
+	// attach строит сторону face по ребру стороны placed, с которой она граничит
+	attach := func(placed, face string) {
+		pv := net[placed]
+		j := indexOf(megaminxNeighbors[placed], face)
+		k := indexOf(megaminxNeighbors[face], placed)
+
+		var v [5]Point
+		v[k], v[(k+1)%5] = pv[(j+1)%5], pv[j]
+		cos, sin := math.Cos(2*math.Pi/5), math.Sin(2*math.Pi/5)
+		for i := 2; i < 5; i++ {
+			p0, p1 := v[(k+i-2)%5], v[(k+i-1)%5]
+			d := Point{X: p1.X - p0.X, Y: p1.Y - p0.Y}
+			v[(k+i)%5] = Point{X: p1.X + d.X*cos - d.Y*sin, Y: p1.Y + d.X*sin + d.Y*cos}
+		}
+		net[face] = v
+	}
+
+	for _, face := range megaminxNeighbors["U"] {
+		attach("U", face)
+	}
+	attach("F", "DR")
+	attach("DR", "D")
+	for _, face := range megaminxNeighbors["D"] {
+		if face != "DR" {
+			attach("D", face)
+		}
+	}
+	return net
+}
+
+// indexOf возвращает номер ребра, через которое сторона граничит с face
+func indexOf(neighbors [5]string, face string) int {
+	for i, n := range neighbors {
+		if n == face {
+			return i
+		}
+	}
+	return -1
+}
+
+// GenerateUnfoldedMegaminx генерирует SVG развёртку мегаминкса
+func GenerateUnfoldedMegaminx(megaminx Megaminx) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	edge := float64(megaminx.Order * megaminxStep)
+	net := megaminxNet(edge)
+
+	// Считаем размер рамки (viewBox) и сдвиг развёртки в неё
+	var outlines [][]Point
+	for _, face := range megaminxFaces {
+		v := net[face]
+		outlines = append(outlines, v[:])
+	}
+	offset, viewBoxSize := fitOutlines(outlines, megaminxBorder)
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, megaminx.Rotate)
+	builder.WriteString(fmt.Sprintf("\r\n<g transform=\"translate(%.2f %.2f)\">", offset.X, offset.Y))
+
+	// Создаём основу (base)
+	generateMegaminxBase(&builder, megaminx, outlines)
+
+	// Создаём стороны (side)
+	for _, face := range megaminxFaces {
+		generateMegaminxFace(&builder, megaminx, face, net[face])
+	}
+
+	builder.WriteString("\r\n</g>")
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, megaminx.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// GenerateFlatMegaminx генерирует SVG картинку верхней стороны мегаминкса (последний слой)
+// с полосками соседних сторон по краям, как GenerateFlatCube
+func GenerateFlatMegaminx(megaminx Megaminx) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	edge := float64(megaminx.Order * megaminxStep)
+	up := megaminxPentagon(Point{}, edge)
+
+	// Полоски сторон верхнего пояса лежат снаружи рёбер U на расстоянии от 1 до 7
+	const stripFrom, stripTo = 1.0, 7.0
+	var outlines [][]Point
+	outlines = append(outlines, up[:])
+	for j := range up {
+		p0, p1 := up[j], up[(j+1)%5]
+		outlines = append(outlines, []Point{offsetPoint(p0, p1, 0, stripTo), offsetPoint(p0, p1, 1, stripTo)})
+	}
+	offset, viewBoxSize := fitOutlines(outlines, 0)
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, megaminx.Rotate)
+	builder.WriteString(fmt.Sprintf("\r\n<g transform=\"translate(%.2f %.2f)\">", offset.X, offset.Y))
+
+	// Создаём основу (base)
+	generateMegaminxBase(&builder, megaminx, [][]Point{up[:]})
+
+	// Создаём верхнюю сторону
+	generateMegaminxFace(&builder, megaminx, "U", up)
+
+	// Создаём полоски сторон верхнего пояса: наклейки у ребра e2 соседней стороны
+	// идут от её вершины V2 (это вершина Vj+1 стороны U) к V3
+	slant := math.Tan(math.Pi / 5)
+	indexes := megaminxEdgeStickers(megaminx.Order)
+	n := megaminx.Order
+	for j, face := range megaminxNeighbors["U"] {
+		p0, p1 := up[j], up[(j+1)%5]
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", strings.ToLower(face)))
+		for s := 0; s < n; s++ {
+			color := megaminx.Colors[face][indexes[n-1-s]]
+
+			// Отрезок ребра под наклейкой; у вершин пятиугольника полоска срезана по биссектрисе угла
+			t0, t1 := float64(s)/float64(n), float64(s+1)/float64(n)
+			gap := megaminxGap / edge
+			from := func(h float64) float64 { return t0 + gap }
+			to := func(h float64) float64 { return t1 - gap }
+			if s == 0 {
+				from = func(h float64) float64 { return t0 + (megaminxGap-h*slant)/edge }
+			}
+			if s == n-1 {
+				to = func(h float64) float64 { return t1 - (megaminxGap-h*slant)/edge }
+			}
+			strip := []Point{
+				offsetPoint(p0, p1, from(stripFrom), stripFrom),
+				offsetPoint(p0, p1, to(stripFrom), stripFrom),
+				offsetPoint(p0, p1, to(stripTo), stripTo),
+				offsetPoint(p0, p1, from(stripTo), stripTo),
+			}
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%s-%d\" d=\"%s\" style=\"fill: %s\"/>",
+				strings.ToLower(face), s+1, roundedPolygonPath(strip, 2), colorMapRGBA[color]))
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	builder.WriteString("\r\n</g>")
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, megaminx.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// generateMegaminxBase рисует основу под сторонами
+func generateMegaminxBase(builder *strings.Builder, megaminx Megaminx, outlines [][]Point) {
+	colorBase := colorMapRGBA[megaminx.Base]
+	builder.WriteString("\r\n\t<g id=\"base\">")
+	for _, outline := range outlines {
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"%s\" style=\"fill: %s; stroke: %s; stroke-width: %d; stroke-linejoin: round\"/>",
+			polygonPath(outline), colorBase, colorBase, 2*megaminxBorder))
+	}
+	builder.WriteString("\r\n\t</g>")
+}
+
+// generateMegaminxFace рисует наклейки стороны face с вершинами v
+func generateMegaminxFace(builder *strings.Builder, megaminx Megaminx, face string, v [5]Point) {
+	id := strings.ToLower(face)
+	builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", id))
+	for i, cell := range megaminxCells(v, megaminx.Order) {
+		color := megaminx.Colors[face][i]
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%s-%d\" d=\"%s\" style=\"fill: %s\"/>",
+			id, i+1, roundedPolygonPath(insetPolygon(cell, megaminxGap), megaminxRound), colorMapRGBA[color]))
+	}
+	builder.WriteString("\r\n\t</g>")
+}
+
+// offsetPoint возвращает точку на отрезке p0–p1 (t от 0 до 1), сдвинутую на h наружу
+// (влево от направления p0→p1, если вершины идут по часовой стрелке)
+func offsetPoint(p0, p1 Point, t, h float64) Point {
+	dx, dy := p1.X-p0.X, p1.Y-p0.Y
+	length := math.Hypot(dx, dy)
+	nx, ny := dy/length, -dx/length
+	return Point{X: p0.X + dx*t + nx*h, Y: p0.Y + dy*t + ny*h}
+}
+
+// fitOutlines считает сдвиг и размер рамки, в которую помещаются контуры с отступом margin
+func fitOutlines(outlines [][]Point, margin float64) (Point, Point) {
+	minP := Point{X: math.Inf(1), Y: math.Inf(1)}
+	maxP := Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, outline := range outlines {
+		for _, p := range outline {
+			minP = Point{X: math.Min(minP.X, p.X), Y: math.Min(minP.Y, p.Y)}
+			maxP = Point{X: math.Max(maxP.X, p.X), Y: math.Max(maxP.Y, p.Y)}
+		}
+	}
+	offset := Point{X: margin - minP.X, Y: margin - minP.Y}
+	size := Point{X: maxP.X - minP.X + 2*margin, Y: maxP.Y - minP.Y + 2*margin}
+	return offset, size
+}
+
+// insetPolygon сдвигает стороны выпуклого многоугольника внутрь на distance
+func insetPolygon(points []Point, distance float64) []Point {
+	n := len(points)
+
+	// Направление обхода: знак площади
+	area := 0.0
+	for i, p := range points {
+		q := points[(i+1)%n]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	sign := 1.0
+	if area < 0 {
+		sign = -1
+	}
+
+	// Сдвинутые прямые сторон: точка и направление
+	type line struct{ p, d Point }
+	lines := make([]line, n)
+	for i, p := range points {
+		q := points[(i+1)%n]
+		dx, dy := q.X-p.X, q.Y-p.Y
+		length := math.Hypot(dx, dy)
+		if length == 0 {
+			lines[i] = line{p: p}
+			continue
+		}
+		nx, ny := -dy/length*sign, dx/length*sign
+		lines[i] = line{p: Point{X: p.X + nx*distance, Y: p.Y + ny*distance}, d: Point{X: dx, Y: dy}}
+	}
+
+	// Новые вершины — пересечения соседних прямых
+	result := make([]Point, 0, n)
+	for i := range points {
+		l1, l2 := lines[(i+n-1)%n], lines[i]
+		cross := l1.d.X*l2.d.Y - l1.d.Y*l2.d.X
+		if math.Abs(cross) < 1e-9 {
+			continue
+		}
+		t := ((l2.p.X-l1.p.X)*l2.d.Y - (l2.p.Y-l1.p.Y)*l2.d.X) / cross
+		result = append(result, Point{X: l1.p.X + l1.d.X*t, Y: l1.p.Y + l1.d.Y*t})
+	}
+	return result
+}
+
+// roundedPolygonPath строит атрибут d многоугольника со скруглёнными углами радиуса radius
+func roundedPolygonPath(points []Point, radius float64) string {
+	n := len(points)
+	var d strings.Builder
+	for i, corner := range points {
+		prev, next := points[(i+n-1)%n], points[(i+1)%n]
+
+		// Скругление не длиннее половины прилегающих сторон
+		cut := func(to Point) Point {
+			length := math.Hypot(to.X-corner.X, to.Y-corner.Y)
+			if length == 0 {
+				return corner
+			}
+			t := math.Min(radius, length/2) / length
+			return Point{X: corner.X + (to.X-corner.X)*t, Y: corner.Y + (to.Y-corner.Y)*t}
+		}
+		from, to := cut(prev), cut(next)
+
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		d.WriteString(fmt.Sprintf("%s%.2f %.2fQ%.2f %.2f %.2f %.2f", command, from.X, from.Y, corner.X, corner.Y, to.X, to.Y))
+	}
+	d.WriteString("z")
+	return d.String()
+}
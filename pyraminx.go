@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Pyraminx struct {
+	Order  int             // Порядок пирамидки (число рядов наклеек на стороне)
+	Colors map[Side][]rune // Карта для хранения цветов каждой стороны
+	Rotate float64         // Угол поворота картинки в градусах
+}
+
+// pyraminxFace треугольная сторона пирамидки. Наклейки перечисляются рядами
+// от вершины Apex к ребру Left–Right, в каждом ряду слева направо
+type pyraminxFace struct {
+	Apex, Left, Right Point3
+}
+
+// Порядок сторон в строке цветов
+var pyraminxSides = [...]Side{Front, Left, Right, Down}
+
+// Цвета сторон собранной пирамидки по умолчанию
+var pyraminxSchemeColors = map[Side]string{
+	Front: "G",
+	Left:  "R",
+	Right: "B",
+	Down:  "Y",
+}
+
+// Номера наклеек классической пирамидки (порядок 3) по типам деталей: вершины, центры и рёбра
+var pyraminxPieceStickers = [...][]int{{0, 4, 8}, {2, 5, 7}, {1, 3, 6}}
+
+// Параметры построения пирамидки
+const (
+	pyraminxStep   = 56   // Длина стороны наклейки
+	pyraminxGap    = 0.16 // Доля, на которую наклейка уменьшается к своему центру
+	pyraminxRound  = 0.12 // Радиус скругления углов наклейки относительно её стороны
+	pyraminxBorder = 9    // Толщина обводки основы
+)
+
+// ParsePyraminxParams парсит параметры для SVG картинки пирамидки.
+// Цвета указываются в порядке {front}-{left}-{right}-{down}-{base}
+func ParsePyraminxParams(pDimensions, pColors string) (Pyraminx, error) {
+
+	// Извлечение порядка из строки pDimensions
+	order, err := strconv.Atoi(pDimensions)
+	if err != nil {
+		return Pyraminx{}, fmt.Errorf("invalid dimension values, expected integer values")
+	}
+	if order < 1 || order > 64 {
+		return Pyraminx{}, fmt.Errorf("dimension values must be between 1 and 64")
+	}
+
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrDefault := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	// Инициализация структуры Pyraminx
+	pyraminx := Pyraminx{
+		Order:  order,
+		Colors: make(map[Side][]rune),
+	}
+
+	// Парсинг цветов для каждой стороны (order² наклеек). Сторону классической пирамидки
+	// можно задать и тремя буквами: цветами вершин, центров и рёбер
+	for i, side := range pyraminxSides {
+		color := getColorOrDefault(i, pyraminxSchemeColors[side])
+		if order == 3 && len(color) == len(pyraminxPieceStickers) {
+			pyraminx.Colors[side] = make([]rune, order*order)
+			for piece, stickers := range pyraminxPieceStickers {
+				for _, sticker := range stickers {
+					pyraminx.Colors[side][sticker] = rune(color[piece])
+				}
+			}
+			continue
+		}
+		pyraminx.Colors[side] = stringToRuneGrid(color, order*order, 1)[0]
+	}
+
+	// Цвет фона (base) будет последним в массиве Colors
+	pyraminx.Colors[Base] = stringToRuneGrid(getColorOrDefault(4, "K"), 1, 1)[0]
+
+	return pyraminx, nil
+}
+
+// pyraminxModel возвращает стороны правильного тетраэдра, стоящего на стороне D.
+// Каждая сторона задана так, как её видно снаружи: F, L и R — вершиной вверх, D — задней вершиной вверх
+func pyraminxModel(order int) map[Side]pyraminxFace {
+	edge := float64(order * pyraminxStep)
+	height := edge * math.Sqrt(2.0/3.0)
+	radius := edge / math.Sqrt(3)
+
+	top := Point3{Y: 0.75 * height}
+	frontLeft := Point3{X: -edge / 2, Y: -0.25 * height, Z: radius / 2}
+	frontRight := Point3{X: edge / 2, Y: -0.25 * height, Z: radius / 2}
+	back := Point3{Y: -0.25 * height, Z: -radius}
+
+	return map[Side]pyraminxFace{
+		Front: {Apex: top, Left: frontLeft, Right: frontRight},
+		Left:  {Apex: top, Left: back, Right: frontLeft},
+		Right: {Apex: top, Left: frontRight, Right: back},
+		Down:  {Apex: back, Left: frontRight, Right: frontLeft},
+	}
+}
+
+// pyraminxNet возвращает развёртку пирамидки (в плоскости XY, ось Y вверх): большой треугольник
+// вершиной вниз, в центре которого сторона F, сверху по бокам L и R, снизу D
+func pyraminxNet(order int) map[Side]pyraminxFace {
+	edge := float64(order * pyraminxStep)
+	height := edge * math.Sqrt(3) / 2
+
+	top := Point3{}
+	frontLeft := Point3{X: -edge / 2, Y: -height}
+	frontRight := Point3{X: edge / 2, Y: -height}
+
+	return map[Side]pyraminxFace{
+		Front: {Apex: top, Left: frontLeft, Right: frontRight},
+		Left:  {Apex: top, Left: Point3{X: -edge}, Right: frontLeft},
+		Right: {Apex: top, Left: frontRight, Right: Point3{X: edge}},
+		Down:  {Apex: Point3{Y: -2 * height}, Left: frontRight, Right: frontLeft},
+	}
+}
+
+// triangles возвращает треугольники наклеек стороны в порядке строки цветов
+func (f pyraminxFace) triangles(order int) [][3]Point3 {
+	// Узел треугольной сетки: ряд r от вершины, k-й слева
+	node := func(r, k int) Point3 {
+		return f.Apex.add(f.Left.sub(f.Apex).scale(float64(r-k) / float64(order))).
+			add(f.Right.sub(f.Apex).scale(float64(k) / float64(order)))
+	}
+
+	var triangles [][3]Point3
+	for r := 0; r < order; r++ {
+		for i := 0; i < 2*r+1; i++ {
+			k := i / 2
+			if i%2 == 0 {
+				// Треугольник вершиной к Apex
+				triangles = append(triangles, [3]Point3{node(r, k), node(r+1, k), node(r+1, k+1)})
+			} else {
+				// Перевёрнутый треугольник
+				triangles = append(triangles, [3]Point3{node(r, k), node(r+1, k+1), node(r, k+1)})
+			}
+		}
+	}
+	return triangles
+}
+
+// pyraminxDrawnFace сторона пирамидки, спроецированная на картинку
+type pyraminxDrawnFace struct {
+	Side     Side
+	Depth    float64    // Удалённость от зрителя (для порядка художника)
+	Outline  [3]Point   // Контур стороны
+	Stickers [][3]Point // Треугольники наклеек
+}
+
+// projectPyraminx проецирует стороны на картинку (ортогонально).
+// Если cull, стороны, обращённые от зрителя, пропускаются
+func projectPyraminx(order int, faces map[Side]pyraminxFace, view cameraView, cull bool) []pyraminxDrawnFace {
+	project := func(p Point3) Point {
+		r := view.rotate(p)
+		return Point{X: r.X, Y: -r.Y}
+	}
+
+	var drawn []pyraminxDrawnFace
+	for _, side := range pyraminxSides {
+		face := faces[side]
+		outline := [3]Point{project(face.Apex), project(face.Left), project(face.Right)}
+
+		// Сторона видна, если её вершины идут в том же направлении, что и на развёртке
+		area := (outline[1].X-outline[0].X)*(outline[2].Y-outline[0].Y) - (outline[1].Y-outline[0].Y)*(outline[2].X-outline[0].X)
+		if cull && area >= -1e-6 {
+			continue
+		}
+
+		d := pyraminxDrawnFace{Side: side, Outline: outline}
+		center := face.Apex.add(face.Left).add(face.Right).scale(1.0 / 3)
+		d.Depth = -view.rotate(center).Z
+		for _, t := range face.triangles(order) {
+			d.Stickers = append(d.Stickers, [3]Point{project(t[0]), project(t[1]), project(t[2])})
+		}
+		drawn = append(drawn, d)
+	}
+
+	// Порядок художника: дальние стороны рисуются первыми
+	sort.SliceStable(drawn, func(i, j int) bool { return drawn[i].Depth > drawn[j].Depth })
+	return drawn
+}
+
+// GenerateIsometricPyraminx генерирует SVG картинку пирамидки, повёрнутой ребром F–R к зрителю
+func GenerateIsometricPyraminx(pyraminx Pyraminx) string {
+	view := newCameraView(Camera{Yaw: 60, Pitch: 20}, 0)
+	return generatePyraminx(pyraminx, projectPyraminx(pyraminx.Order, pyraminxModel(pyraminx.Order), view, true))
+}
+
+// GenerateFlatPyraminx генерирует SVG картинку пирамидки сверху (стороны F, L и R)
+func GenerateFlatPyraminx(pyraminx Pyraminx) string {
+	view := newCameraView(Camera{Pitch: 90}, 0)
+	return generatePyraminx(pyraminx, projectPyraminx(pyraminx.Order, pyraminxModel(pyraminx.Order), view, true))
+}
+
+// GenerateNetPyraminx генерирует SVG развёртку пирамидки
+func GenerateNetPyraminx(pyraminx Pyraminx) string {
+	view := newCameraView(Camera{}, 0)
+	return generatePyraminx(pyraminx, projectPyraminx(pyraminx.Order, pyraminxNet(pyraminx.Order), view, false))
+}
+
+// generatePyraminx строит SVG из спроецированных сторон
+func generatePyraminx(pyraminx Pyraminx, faces []pyraminxDrawnFace) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Считаем размер рамки (viewBox) по контурам сторон
+	margin := float64(pyraminxBorder + 3)
+	minP := Point{X: math.Inf(1), Y: math.Inf(1)}
+	maxP := Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, face := range faces {
+		for _, p := range face.Outline {
+			minP = Point{X: math.Min(minP.X, p.X), Y: math.Min(minP.Y, p.Y)}
+			maxP = Point{X: math.Max(maxP.X, p.X), Y: math.Max(maxP.Y, p.Y)}
+		}
+	}
+	offset := Point{X: margin - minP.X, Y: margin - minP.Y}
+	viewBoxSize := Point{X: maxP.X - minP.X + 2*margin, Y: maxP.Y - minP.Y + 2*margin}
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, pyraminx.Rotate)
+
+	// Сдвигаем сцену в рамку
+	builder.WriteString(fmt.Sprintf("\r\n<g transform=\"translate(%.2f %.2f)\">", offset.X, offset.Y))
+
+	// Создаём основу (base)
+	colorBase := colorMapRGBA[pyraminx.Colors[Base][0]]
+	builder.WriteString("\r\n\t<g id=\"base\">")
+	for _, face := range faces {
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"base-%s\" d=\"%s\" style=\"fill: %s; stroke: %s; stroke-width: %d; stroke-linejoin: round\"/>",
+			face.Side, polygonPath(face.Outline[:]), colorBase, colorBase, 2*pyraminxBorder))
+	}
+	builder.WriteString("\r\n\t</g>")
+
+	// Создаём стороны (side)
+	for _, face := range faces {
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", face.Side))
+		for i, t := range face.Stickers {
+			color := pyraminx.Colors[face.Side][i]
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%c-%d\" d=\"%s\" style=\"fill: %s\"/>",
+				face.Side.String()[0], i+1, roundedTriangle(t), colorMapRGBA[color]))
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	builder.WriteString("\r\n</g>")
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, pyraminx.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// roundedTriangle строит атрибут d наклейки-треугольника, уменьшенной к центру, со скруглёнными углами
+func roundedTriangle(t [3]Point) string {
+	center := Point{X: (t[0].X + t[1].X + t[2].X) / 3, Y: (t[0].Y + t[1].Y + t[2].Y) / 3}
+	var corners [3]Point
+	for i, p := range t {
+		corners[i] = Point{X: p.X + (center.X-p.X)*pyraminxGap, Y: p.Y + (center.Y-p.Y)*pyraminxGap}
+	}
+
+	var d strings.Builder
+	for i, corner := range corners {
+		prev, next := corners[(i+2)%3], corners[(i+1)%3]
+		from := Point{X: corner.X + (prev.X-corner.X)*pyraminxRound, Y: corner.Y + (prev.Y-corner.Y)*pyraminxRound}
+		to := Point{X: corner.X + (next.X-corner.X)*pyraminxRound, Y: corner.Y + (next.Y-corner.Y)*pyraminxRound}
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		d.WriteString(fmt.Sprintf("%s%.2f %.2fQ%.2f %.2f %.2f %.2f", command, from.X, from.Y, corner.X, corner.Y, to.X, to.Y))
+	}
+	d.WriteString("z")
+	return d.String()
+}
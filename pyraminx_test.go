@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestPyraminxPieceStickers(t *testing.T) {
+	// Вершина — наклейка в углу стороны, центр — её сосед по стороне, остальные — рёбра
+	face := pyraminxNet(3)[Front]
+	triangles := face.triangles(3)
+	has := func(triangle [3]Point3, p Point3) bool {
+		return triangle[0] == p || triangle[1] == p || triangle[2] == p
+	}
+	shared := func(a, b [3]Point3) int {
+		n := 0
+		for _, p := range a {
+			if has(b, p) {
+				n++
+			}
+		}
+		return n
+	}
+
+	kinds := make([]int, len(triangles))
+	for i := range kinds {
+		kinds[i] = 2
+	}
+	for i, triangle := range triangles {
+		if has(triangle, face.Apex) || has(triangle, face.Left) || has(triangle, face.Right) {
+			kinds[i] = 0
+			for j, other := range triangles {
+				if shared(triangle, other) == 2 {
+					kinds[j] = 1
+				}
+			}
+		}
+	}
+
+	for kind, stickers := range pyraminxPieceStickers {
+		for _, sticker := range stickers {
+			if kinds[sticker] != kind {
+				t.Errorf("sticker %d is of kind %d, want %d", sticker, kinds[sticker], kind)
+			}
+		}
+	}
+}
+
+func TestParsePyraminxParams(t *testing.T) {
+	tests := []struct {
+		order, colors string
+		side          Side
+		want          string
+	}{
+		{"3", "", Front, "GGGGGGGGG"},
+		{"3", "GGGGRGGGG", Front, "GGGGRGGGG"},
+		{"3", "-XGX", Left, "XXGXXGXGX"},
+		{"3", "WYR", Front, "WRYRWYRYW"},
+		{"2", "WYR", Front, "WYRX"},
+		{"1", "--B", Right, "B"},
+	}
+
+	for _, tt := range tests {
+		pyraminx, err := ParsePyraminxParams(tt.order, tt.colors)
+		if err != nil {
+			t.Fatalf("ParsePyraminxParams(%q, %q): %v", tt.order, tt.colors, err)
+		}
+		if got := string(pyraminx.Colors[tt.side]); got != tt.want {
+			t.Errorf("ParsePyraminxParams(%q, %q) %s = %s, want %s", tt.order, tt.colors, tt.side, got, tt.want)
+		}
+	}
+
+	for _, order := range []string{"0", "65", "x", ""} {
+		if _, err := ParsePyraminxParams(order, ""); err == nil {
+			t.Errorf("ParsePyraminxParams(%q) returned no error", order)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Matrix аффинное преобразование [a b c d e f] (как в атрибуте transform):
+// x' = a*x + c*y + e, y' = b*x + d*y + f
+type Matrix [6]float64
+
+// IdentityMatrix возвращает единичное преобразование
+func IdentityMatrix() Matrix {
+	return Matrix{1, 0, 0, 1, 0, 0}
+}
+
+// Multiply возвращает преобразование m, применённое после n (m × n)
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+// Apply применяет преобразование к точке
+func (m Matrix) Apply(p Point) Point {
+	return Point{X: m[0]*p.X + m[2]*p.Y + m[4], Y: m[1]*p.X + m[3]*p.Y + m[5]}
+}
+
+// Scale возвращает средний коэффициент масштабирования преобразования
+func (m Matrix) Scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// Rasterizer закрашивает многоугольники со сглаживанием.
+// Покрытие пикселей считается накоплением площадей под рёбрами (как в font-rs),
+// поэтому пересекающиеся контуры одного направления объединяются
+type Rasterizer struct {
+	Width, Height int
+	stride        int
+	acc           []float64
+	minX, minY    int
+	maxX, maxY    int
+}
+
+// NewRasterizer создаёт растеризатор для картинки размером width x height
+func NewRasterizer(width, height int) *Rasterizer {
+	r := &Rasterizer{Width: width, Height: height, stride: width + 2}
+	r.acc = make([]float64, r.stride*height)
+	r.resetBounds()
+	return r
+}
+
+func (r *Rasterizer) resetBounds() {
+	r.minX, r.minY = r.Width, r.Height
+	r.maxX, r.maxY = -1, -1
+}
+
+// AddPolygon добавляет замкнутый многоугольник (в пикселях)
+func (r *Rasterizer) AddPolygon(points []Point) {
+	for i := range points {
+		r.line(points[i], points[(i+1)%len(points)])
+	}
+}
+
+// line добавляет ребро многоугольника
+func (r *Rasterizer) line(p0, p1 Point) {
+	// Точки за пределами картинки по горизонтали прижимаются к краю: площадь справа от ребра не меняется
+	p0.X = math.Max(0, math.Min(float64(r.Width), p0.X))
+	p1.X = math.Max(0, math.Min(float64(r.Width), p1.X))
+	if p0.Y == p1.Y {
+		return
+	}
+
+	dir := 1.0
+	if p0.Y > p1.Y {
+		dir, p0, p1 = -1, p1, p0
+	}
+	dxdy := (p1.X - p0.X) / (p1.Y - p0.Y)
+	x := p0.X
+	if p0.Y < 0 {
+		x -= p0.Y * dxdy
+	}
+
+	y0 := int(math.Max(0, math.Floor(p0.Y)))
+	y1 := int(math.Min(float64(r.Height), math.Ceil(p1.Y)))
+	if y0 < r.minY {
+		r.minY = y0
+	}
+	if y1-1 > r.maxY {
+		r.maxY = y1 - 1
+	}
+
+	for y := y0; y < y1; y++ {
+		row := r.acc[y*r.stride : (y+1)*r.stride]
+		dy := math.Min(float64(y+1), p1.Y) - math.Max(float64(y), p0.Y)
+		xNext := x + dxdy*dy
+		d := dy * dir
+
+		// Из-за погрешностей вычислений координаты могут чуть выйти за края
+		x0 := math.Max(0, math.Min(float64(r.Width), math.Min(x, xNext)))
+		x1 := math.Max(0, math.Min(float64(r.Width), math.Max(x, xNext)))
+		x0Floor := math.Floor(x0)
+		x0i := int(x0Floor)
+		x1Ceil := math.Ceil(x1)
+		x1i := int(x1Ceil)
+		if x0i < r.minX {
+			r.minX = x0i
+		}
+		if x1i > r.maxX {
+			r.maxX = x1i
+		}
+
+		if x1i <= x0i+1 {
+			// Ребро проходит внутри одного пикселя
+			xmf := 0.5*(x0+x1) - x0Floor
+			row[x0i] += d - d*xmf
+			row[x0i+1] += d * xmf
+		} else {
+			s := 1 / (x1 - x0)
+			x0f := x0 - x0Floor
+			a0 := 0.5 * s * (1 - x0f) * (1 - x0f)
+			x1f := x1 - x1Ceil + 1
+			am := 0.5 * s * x1f * x1f
+			row[x0i] += d * a0
+			if x1i == x0i+2 {
+				row[x0i+1] += d * (1 - a0 - am)
+			} else {
+				a1 := s * (1.5 - x0f)
+				row[x0i+1] += d * (a1 - a0)
+				for xi := x0i + 2; xi < x1i-1; xi++ {
+					row[xi] += d * s
+				}
+				a2 := a1 + float64(x1i-x0i-3)*s
+				row[x1i-1] += d * (1 - a2 - am)
+			}
+			row[x1i] += d * am
+		}
+		x = xNext
+	}
+}
+
+// Composite закрашивает накопленную фигуру цветом c поверх картинки img и очищает накопитель
+func (r *Rasterizer) Composite(img *image.RGBA, c color.NRGBA, opacity float64) {
+	if r.maxY < 0 {
+		r.resetBounds()
+		return
+	}
+
+	sa := float64(c.A) / 255 * opacity
+	sr, sg, sb := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+
+	maxX := r.maxX + 1
+	if maxX > r.stride-1 {
+		maxX = r.stride - 1
+	}
+	for y := r.minY; y <= r.maxY; y++ {
+		row := r.acc[y*r.stride : (y+1)*r.stride]
+		sum := 0.0
+		for x := r.minX; x <= maxX; x++ {
+			sum += row[x]
+			row[x] = 0
+			if x >= r.Width {
+				continue
+			}
+			coverage := math.Min(1, math.Abs(sum))
+			if coverage < 1.0/512 {
+				continue
+			}
+
+			// Смешивание с учётом прозрачности (картинка хранится с предумноженной альфой)
+			a := sa * coverage
+			i := img.PixOffset(x, y)
+			pix := img.Pix[i : i+4 : i+4]
+			k := 1 - a
+			pix[0] = uint8(math.Round((sr*a)*255 + float64(pix[0])*k))
+			pix[1] = uint8(math.Round((sg*a)*255 + float64(pix[1])*k))
+			pix[2] = uint8(math.Round((sb*a)*255 + float64(pix[2])*k))
+			pix[3] = uint8(math.Round(a*255 + float64(pix[3])*k))
+		}
+	}
+	r.resetBounds()
+}
+
+// flattenCubic разбивает кубическую кривую Безье на отрезки (без начальной точки)
+func flattenCubic(p0, p1, p2, p3 Point, segments int) []Point {
+	points := make([]Point, 0, segments)
+	for i := 1; i <= segments; i++ {
+		t := float64(i) / float64(segments)
+		mt := 1 - t
+		points = append(points, Point{
+			X: mt*mt*mt*p0.X + 3*mt*mt*t*p1.X + 3*mt*t*t*p2.X + t*t*t*p3.X,
+			Y: mt*mt*mt*p0.Y + 3*mt*mt*t*p1.Y + 3*mt*t*t*p2.Y + t*t*t*p3.Y,
+		})
+	}
+	return points
+}
+
+// flattenQuadratic разбивает квадратичную кривую Безье на отрезки (без начальной точки)
+func flattenQuadratic(p0, p1, p2 Point, segments int) []Point {
+	points := make([]Point, 0, segments)
+	for i := 1; i <= segments; i++ {
+		t := float64(i) / float64(segments)
+		mt := 1 - t
+		points = append(points, Point{
+			X: mt*mt*p0.X + 2*mt*t*p1.X + t*t*p2.X,
+			Y: mt*mt*p0.Y + 2*mt*t*p1.Y + t*t*p2.Y,
+		})
+	}
+	return points
+}
+
+// flattenArc разбивает дугу эллипса SVG (команда A) на отрезки (без начальной точки)
+func flattenArc(p0 Point, rx, ry, rotation float64, largeArc, sweep bool, p1 Point, scale float64) []Point {
+	if p0 == p1 {
+		return nil
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []Point{p1}
+	}
+
+	// Перевод из параметризации по концам в параметризацию по центру (SVG 1.1, приложение F.6)
+	phi := rotation * math.Pi / 180
+	cosPhi, sinPhi := math.Cos(phi), math.Sin(phi)
+	dx, dy := (p0.X-p1.X)/2, (p0.Y-p1.Y)/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry)
+	if lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	k := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		k = -k
+	}
+	cx1 := k * rx * y1 / ry
+	cy1 := -k * ry * x1 / rx
+	cx := cosPhi*cx1 - sinPhi*cy1 + (p0.X+p1.X)/2
+	cy := sinPhi*cx1 + cosPhi*cy1 + (p0.Y+p1.Y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	segments := curveSegments(math.Abs(delta) * math.Max(rx, ry) * scale)
+	points := make([]Point, 0, segments)
+	for i := 1; i <= segments; i++ {
+		t := theta + delta*float64(i)/float64(segments)
+		x, y := rx*math.Cos(t), ry*math.Sin(t)
+		points = append(points, Point{X: cosPhi*x - sinPhi*y + cx, Y: sinPhi*x + cosPhi*y + cy})
+	}
+	points[len(points)-1] = p1
+	return points
+}
+
+// curveSegments возвращает число отрезков для кривой примерной длины length (в пикселях)
+func curveSegments(length float64) int {
+	n := int(math.Ceil(math.Sqrt(length) * 1.5))
+	if n < 2 {
+		return 2
+	}
+	if n > 128 {
+		return 128
+	}
+	return n
+}
+
+// strokePolygons строит многоугольники обводки ломаной толщиной width.
+// Соединения всегда скруглённые, концы — скруглённые, если roundCap
+func strokePolygons(points []Point, closed bool, width float64, roundCap bool, scale float64) [][]Point {
+	var polygons [][]Point
+	half := width / 2
+	if len(points) == 0 || half <= 0 {
+		return nil
+	}
+
+	segmentCount := len(points) - 1
+	if closed {
+		segmentCount = len(points)
+	}
+	for i := 0; i < segmentCount; i++ {
+		a, b := points[i], points[(i+1)%len(points)]
+		length := math.Hypot(b.X-a.X, b.Y-a.Y)
+		if length == 0 {
+			continue
+		}
+		nx, ny := -(b.Y-a.Y)/length*half, (b.X-a.X)/length*half
+		polygons = append(polygons, positive([]Point{
+			{X: a.X + nx, Y: a.Y + ny},
+			{X: b.X + nx, Y: b.Y + ny},
+			{X: b.X - nx, Y: b.Y - ny},
+			{X: a.X - nx, Y: a.Y - ny},
+		}))
+	}
+
+	// Скруглённые соединения и концы
+	for i, p := range points {
+		isEnd := !closed && (i == 0 || i == len(points)-1)
+		if isEnd && !roundCap {
+			continue
+		}
+		polygons = append(polygons, circlePolygon(p, half, scale))
+	}
+
+	return polygons
+}
+
+// circlePolygon возвращает окружность в виде многоугольника
+func circlePolygon(center Point, radius, scale float64) []Point {
+	segments := curveSegments(2*math.Pi*radius*scale) + 4
+	points := make([]Point, segments)
+	for i := range points {
+		t := 2 * math.Pi * float64(i) / float64(segments)
+		points[i] = Point{X: center.X + radius*math.Cos(t), Y: center.Y + radius*math.Sin(t)}
+	}
+	return positive(points)
+}
+
+// positive разворачивает многоугольник так, чтобы его обход был положительным,
+// тогда перекрывающиеся части обводки объединяются, а не вычитаются
+func positive(points []Point) []Point {
+	area := 0.0
+	for i := range points {
+		a, b := points[i], points[(i+1)%len(points)]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	if area < 0 {
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+	return points
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseRotate парсит угол поворота картинки в градусах (по часовой стрелке)
+func ParseRotate(pRotate string) (float64, error) {
+	if pRotate == "" {
+		return 0, nil
+	}
+
+	angle, err := strconv.ParseFloat(pRotate, 64)
+	if err != nil || math.IsNaN(angle) || math.IsInf(angle, 0) {
+		return 0, fmt.Errorf("invalid rotate value %q, expected a number of degrees", pRotate)
+	}
+
+	// Приводим угол к диапазону [0, 360)
+	angle = math.Mod(angle, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return angle, nil
+}
+
+// GenerateViewBox открывает SVG с рамкой (viewBox) для картинки размером width x height.
+// При повороте рамка расширяется до описанного вокруг повёрнутой картинки прямоугольника,
+// а всё содержимое помещается в группу с поворотом вокруг центра
+func GenerateViewBox(builder *strings.Builder, width, height, rotate float64) {
+	if rotate == 0 {
+		builder.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %s %s\">",
+			formatNumber(width), formatNumber(height)))
+		return
+	}
+
+	// Размер прямоугольника, описанного вокруг повёрнутой картинки
+	a := rotate * math.Pi / 180
+	cos, sin := math.Abs(math.Cos(a)), math.Abs(math.Sin(a))
+	boxWidth := width*cos + height*sin
+	boxHeight := width*sin + height*cos
+
+	// Центр картинки остаётся на месте
+	center := Point{X: width / 2, Y: height / 2}
+	builder.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%s %s %s %s\">",
+		formatNumber(center.X-boxWidth/2), formatNumber(center.Y-boxHeight/2), formatNumber(boxWidth), formatNumber(boxHeight)))
+	builder.WriteString(fmt.Sprintf("\r\n<g id=\"rotate\" transform=\"rotate(%s %s %s)\">",
+		formatNumber(rotate), formatNumber(center.X), formatNumber(center.Y)))
+}
+
+// CloseViewBox закрывает группу поворота (если она есть) и SVG
+func CloseViewBox(builder *strings.Builder, rotate float64) {
+	if rotate != 0 {
+		builder.WriteString("\r\n</g>")
+	}
+	builder.WriteString("\r\n</svg>")
+}
+
+// formatNumber выводит число с точностью до сотых без лишних нулей
+func formatNumber(value float64) string {
+	value = math.Round(value*100) / 100
+	if value == 0 {
+		// Избавляемся от "-0"
+		value = 0
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Наибольший поддерживаемый порядок скьюба (1 — скьюб, 2 — мастер-скьюб и т.д.)
+const skewbMaxOrder = 8
+
+// skewbStickerCorners задаёт, в каком углу стороны (0 — левый верхний, 1 — правый верхний,
+// 2 — левый нижний, 3 — правый нижний) лежит каждая из четырёх угловых наклеек.
+// Порядок совпадает с изометрическим видом: сторона U там повёрнута, поэтому её углы идут
+// как UFL, UBL, UFR, UBR при взгляде сверху (задняя сторона наверху)
+var skewbStickerCorners = map[Side][4]int{
+	Front: {0, 1, 2, 3},
+	Left:  {0, 1, 2, 3},
+	Up:    {2, 0, 3, 1},
+	Right: {0, 1, 2, 3},
+	Down:  {0, 1, 2, 3},
+	Back:  {0, 1, 2, 3},
+}
+
+// Углы единичного квадрата стороны в порядке 0 — левый верхний, 1 — правый верхний,
+// 2 — левый нижний, 3 — правый нижний
+var skewbUnitCorners = [4]Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+
+// skewbCuts возвращает положения разрезов скьюба порядка order вдоль диагонали стороны:
+// разрез от угла отсекает треугольник с катетом cut (в долях стороны). У обычного скьюба
+// один разрез через середины рёбер, у порядка n — n разрезов, симметричных относительно него
+func skewbCuts(order int) []float64 {
+	cuts := make([]float64, order)
+	for i := range cuts {
+		a := float64(2*i-(order-1)) / float64(order+1)
+		cuts[i] = (1 + a) / 2
+	}
+	return cuts
+}
+
+// splitPolygon разрезает выпуклый многоугольник прямой n·p = c на две части:
+// где n·p <= c и где n·p >= c. Пустые части возвращаются как nil
+func splitPolygon(points []Point, n Point, c float64) ([]Point, []Point) {
+	var below, above []Point
+	value := func(p Point) float64 { return n.X*p.X + n.Y*p.Y - c }
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		vp, vq := value(p), value(q)
+		if vp <= 1e-12 {
+			below = append(below, p)
+		}
+		if vp >= -1e-12 {
+			above = append(above, p)
+		}
+		// Сторона пересекает прямую: добавляем точку пересечения в обе части
+		if (vp < -1e-12 && vq > 1e-12) || (vp > 1e-12 && vq < -1e-12) {
+			t := vp / (vp - vq)
+			x := Point{X: p.X + (q.X-p.X)*t, Y: p.Y + (q.Y-p.Y)*t}
+			below = append(below, x)
+			above = append(above, x)
+		}
+	}
+	return cleanPolygon(below), cleanPolygon(above)
+}
+
+// cleanPolygon убирает совпадающие соседние вершины и возвращает nil для вырожденных многоугольников
+func cleanPolygon(points []Point) []Point {
+	var result []Point
+	for _, p := range points {
+		if len(result) > 0 && math.Hypot(p.X-result[len(result)-1].X, p.Y-result[len(result)-1].Y) < 1e-9 {
+			continue
+		}
+		result = append(result, p)
+	}
+	if len(result) > 1 && math.Hypot(result[0].X-result[len(result)-1].X, result[0].Y-result[len(result)-1].Y) < 1e-9 {
+		result = result[:len(result)-1]
+	}
+	if len(result) < 3 || math.Abs(polygonArea(result)) < 1e-9 {
+		return nil
+	}
+	return result
+}
+
+// polygonArea возвращает ориентированную площадь многоугольника
+func polygonArea(points []Point) float64 {
+	area := 0.0
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	return area / 2
+}
+
+// polygonCentroid возвращает центр масс многоугольника
+func polygonCentroid(points []Point) Point {
+	var cx, cy float64
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		cross := p.X*q.Y - q.X*p.Y
+		cx += (p.X + q.X) * cross
+		cy += (p.Y + q.Y) * cross
+	}
+	area := polygonArea(points)
+	return Point{X: cx / (6 * area), Y: cy / (6 * area)}
+}
+
+// skewbUnitCells разрезает единичный квадрат стороны (X вправо, Y вниз) разрезами скьюба
+// порядка order: от каждого из четырёх углов идут order прямых, параллельных диагонали
+func skewbUnitCells(order int) [][]Point {
+	cells := [][]Point{{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}}}
+	for _, corner := range skewbUnitCorners {
+		// Направление от угла к центру стороны
+		n := Point{X: 1 - 2*corner.X, Y: 1 - 2*corner.Y}
+		for _, cut := range skewbCuts(order) {
+			c := n.X*corner.X + n.Y*corner.Y + cut
+			var next [][]Point
+			for _, cell := range cells {
+				below, above := splitPolygon(cell, n, c)
+				if below != nil {
+					next = append(next, below)
+				}
+				if above != nil {
+					next = append(next, above)
+				}
+			}
+			cells = next
+		}
+	}
+	return cells
+}
+
+// skewbFaceCells возвращает наклейки стороны side скьюба порядка order в единичном квадрате.
+// Сначала идут четыре угловые наклейки по порядку skewbStickerCorners, затем остальные
+// построчно: сверху вниз и слева направо по их центрам, как сторона нарисована на развёртке
+func skewbFaceCells(side Side, order int) [][]Point {
+	cells := skewbUnitCells(order)
+
+	// Угловая наклейка содержит вершину квадрата
+	cornerCell := func(corner Point) int {
+		for i, cell := range cells {
+			for _, p := range cell {
+				if math.Hypot(p.X-corner.X, p.Y-corner.Y) < 1e-9 {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+
+	var result [][]Point
+	used := make(map[int]bool)
+	for _, corner := range skewbStickerCorners[side] {
+		i := cornerCell(skewbUnitCorners[corner])
+		result = append(result, cells[i])
+		used[i] = true
+	}
+
+	var rest [][]Point
+	for i, cell := range cells {
+		if !used[i] {
+			rest = append(rest, cell)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		a, b := polygonCentroid(rest[i]), polygonCentroid(rest[j])
+		if math.Abs(a.Y-b.Y) > 1e-6 {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return append(result, rest...)
+}
+
+// skewbStickerCount возвращает число наклеек на стороне скьюба порядка order
+func skewbStickerCount(order int) int {
+	return len(skewbUnitCells(order))
+}
+
+// skewbStripSegments возвращает участки полоски вдоль верхнего ребра стороны (в долях ребра):
+// наклейки, которые пересекает прямая на глубине depth от ребра, слева направо
+func skewbStripSegments(order int, depth float64) [][2]float64 {
+	var segments [][2]float64
+	for _, cell := range skewbUnitCells(order) {
+		from, to := math.Inf(1), math.Inf(-1)
+		for i, p := range cell {
+			q := cell[(i+1)%len(cell)]
+			if (p.Y-depth)*(q.Y-depth) > 0 || p.Y == q.Y {
+				continue
+			}
+			x := p.X + (q.X-p.X)*(depth-p.Y)/(q.Y-p.Y)
+			from, to = math.Min(from, x), math.Max(to, x)
+		}
+		if to-from > 1e-9 {
+			segments = append(segments, [2]float64{from, to})
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i][0] < segments[j][0] })
+	return segments
+}
+
+// roundedStickerPath строит скруглённую наклейку из многоугольника детали. Мелкие детали
+// (например, у скьюбов старших порядков) сжимаются не больше, чем позволяет их размер
+func roundedStickerPath(points []Point, gap, radius float64) string {
+	perimeter := 0.0
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		perimeter += math.Hypot(q.X-p.X, q.Y-p.Y)
+	}
+	inradius := 2 * math.Abs(polygonArea(points)) / perimeter
+	gap = math.Min(gap, 0.45*inradius)
+	return roundedPolygonPath(insetPolygon(points, gap), math.Min(radius, inradius-gap))
+}
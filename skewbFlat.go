@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Параметры построения плоских видов скьюба (в точках): сторона обычного скьюба занимает
+// столько же места, сколько сторона кубика 2x2
+const (
+	skewbFace  = 98   // Сторона квадрата, на котором лежат наклейки стороны скьюба порядка 1
+	skewbGap   = 3    // Отступ наклейки от границ детали
+	skewbRound = 5    // Радиус скругления углов наклейки
+	skewbBase  = 7.42 // Радиус скругления основы
+)
+
+// skewbFaceSize возвращает сторону квадрата стороны скьюба порядка order:
+// с каждым порядком сторона растёт на половину стороны обычного скьюба
+func skewbFaceSize(order int) float64 {
+	return skewbFace * float64(order+1) / 2
+}
+
+// ParseFlatSkewbParams парсит параметры для плоской SVG картинки скьюба:
+// сторона U и полоски соседних сторон {up}-{left}-{back}-{right}-{front}-{base}
+func ParseFlatSkewbParams(pDimensions, pColors string) (IsometricSkewb, error) {
+
+	// Извлечение размеров из строки pDimensions
+	order, err := parseSkewbDimensions(pDimensions)
+	if err != nil {
+		return IsometricSkewb{}, err
+	}
+
+	// Инициализация структуры IsometricSkewb
+	skewb := IsometricSkewb{
+		Order:  order,
+		Colors: make(map[Side][]rune),
+	}
+
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrEmpty := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	// Парсинг цветов: сторона U целиком, у соседних сторон — полоска наклеек вдоль ребра с U
+	// (у обычного скьюба это угол, центр и угол)
+	strip := len(skewbFlatStripSegments(order))
+	skewb.Colors[Up] = stringToRuneGrid(getColorOrEmpty(0, "X"), skewbStickerCount(order), 1)[0]
+	skewb.Colors[Left] = stringToRuneGrid(getColorOrEmpty(1, "T"), strip, 1)[0]
+	skewb.Colors[Back] = stringToRuneGrid(getColorOrEmpty(2, "T"), strip, 1)[0]
+	skewb.Colors[Right] = stringToRuneGrid(getColorOrEmpty(3, "T"), strip, 1)[0]
+	skewb.Colors[Front] = stringToRuneGrid(getColorOrEmpty(4, "T"), strip, 1)[0]
+
+	// Цвет фона (base) будет последним в массиве Colors
+	skewb.Colors[Base] = stringToRuneGrid(getColorOrEmpty(5, "K"), 1, 1)[0]
+
+	return skewb, nil
+}
+
+// skewbFlatStripSegments возвращает участки полоски вдоль ребра стороны скьюба порядка order
+// (в долях ребра): наклейки, которые видны на глубине 7 точек от ребра
+func skewbFlatStripSegments(order int) [][2]float64 {
+	return skewbStripSegments(order, 7/skewbFaceSize(order))
+}
+
+// GenerateFlatSkewb генерирует плоскую SVG картинку скьюба: сторону U сверху
+// и полоски соседних сторон по краям, как GenerateFlatCube
+func GenerateFlatSkewb(skewb IsometricSkewb) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	face := skewbFaceSize(skewb.order())
+
+	// Полоски: у каждой стороны наклейки вдоль общего ребра с U.
+	// Для верхней и нижней полосок — начало по X, для левой и правой — по Y
+	type strip struct {
+		Side       Side
+		Horizontal bool
+		Offset     float64 // Положение полоски поперёк
+	}
+	strips := []strip{
+		{Side: Left, Horizontal: false, Offset: 0},
+		{Side: Back, Horizontal: true, Offset: 0},
+		{Side: Right, Horizontal: false, Offset: 8 + face},
+		{Side: Front, Horizontal: true, Offset: 8 + face},
+	}
+
+	// Участки полоски вдоль ребра: крайние начинаются от края основы,
+	// между соседними участками — зазор как между наклейками
+	var segments [][2]float64
+	for _, segment := range skewbFlatStripSegments(skewb.order()) {
+		from, to := 7+face*segment[0]+skewbGap, 7+face*segment[1]-skewbGap
+		if segment[0] == 0 {
+			from = 10
+		}
+		if segment[1] == 1 {
+			to = 4 + face
+		}
+		segments = append(segments, [2]float64{from, to})
+	}
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, 14+face, 14+face, skewb.Rotate)
+
+	// Создаём основу (base)
+	colorBase := skewb.Colors[Base][0]
+	builder.WriteString(fmt.Sprintf("<rect id=\"base\" width=\"%.2f\" height=\"%.2f\" rx=\"%.2f\" style=\"fill: %s\" x=\"3\" y=\"3\"/>",
+		8+face, 8+face, skewbBase, colorMapRGBA[colorBase]))
+
+	// Создаём сторону U
+	generateFlatSkewbFace(&builder, skewb, Up, Point{X: 7, Y: 7})
+
+	// Создаём полоски соседних сторон
+	for _, s := range strips {
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", s.Side.String()))
+		for i, segment := range segments {
+			x, y, w, h := segment[0], s.Offset, segment[1]-segment[0], 6.0
+			if !s.Horizontal {
+				x, y, w, h = y, x, h, w
+			}
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<rect id=\"%c-%d\" x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" rx=\"2.32\" style=\"fill: %s\"/>",
+				s.Side.String()[0], i+1, x, y, w, h, colorMapRGBA[skewb.Colors[s.Side][i]]))
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, skewb.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// generateFlatSkewbFace рисует наклейки стороны скьюба в квадрате с левым верхним углом origin
+func generateFlatSkewbFace(builder *strings.Builder, skewb IsometricSkewb, side Side, origin Point) {
+	size := skewbFaceSize(skewb.order())
+	builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", side.String()))
+	for i, cell := range skewbFaceCells(side, skewb.order()) {
+		polygon := make([]Point, len(cell))
+		for j, p := range cell {
+			polygon[j] = Point{X: origin.X + p.X*size, Y: origin.Y + p.Y*size}
+		}
+		color := skewb.Colors[side][i]
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"%c-%d\" d=\"%s\" style=\"fill: %s\"/>",
+			side.String()[0], i+1, roundedStickerPath(polygon, skewbGap, skewbRound), colorMapRGBA[color]))
+	}
+	builder.WriteString("\r\n\t</g>")
+}
@@ -7,34 +7,54 @@ import (
 )
 
 type IsometricSkewb struct {
+	Order      int                         // Порядок скьюба: 1 — скьюб, 2 — мастер-скьюб и т.д.
 	Colors     map[Side][]rune             // Карта для хранения цветов каждой стороны
 	SideParams map[Side]IsometricSkewbSide // Параметры боковой стороны скьюба
+	Rotate     float64                     // Угол поворота картинки в градусах
 }
 
 // Структура, хранящая параметры для построения элементов на стороне скьюба
 type IsometricSkewbSide struct {
-	Drawn [5]string // Атрибут d тега path в SVG, хранящий в себе построение фигуры
+	Drawn []string // Атрибут d тега path в SVG, хранящий в себе построение фигуры
 }
 
-// ParseIsometricSkewbParams парсит параметры для изометрической SVG картинки скьюба
-func ParseIsometricSkewbParams(pDimensions, pColors string) (IsometricSkewb, error) {
+// order возвращает порядок скьюба; незаданный порядок означает обычный скьюб
+func (skewb IsometricSkewb) order() int {
+	if skewb.Order < 1 {
+		return 1
+	}
+	return skewb.Order
+}
 
-	// Извлечение размеров из строки pDimensions
+// parseSkewbDimensions извлекает размер скьюба из строки pDimensions
+func parseSkewbDimensions(pDimensions string) (int, error) {
 	dimensions := strings.Split(pDimensions, "x")
 	if len(dimensions) != 1 {
-		return IsometricSkewb{}, fmt.Errorf("invalid dimensions: expected 1 dimensions")
+		return 0, fmt.Errorf("invalid dimensions: expected 1 dimensions")
 	}
 
-	dX, err1 := strconv.Atoi(dimensions[0])
-	if err1 != nil {
-		return IsometricSkewb{}, fmt.Errorf("invalid dimension values, expected integer values")
+	dX, err := strconv.Atoi(dimensions[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid dimension values, expected integer values")
+	}
+	if dX < 1 || dX > skewbMaxOrder {
+		return 0, fmt.Errorf("dimension values must be between 1 and %d", skewbMaxOrder)
 	}
-	if dX < 1 || dX > 1 {
-		return IsometricSkewb{}, fmt.Errorf("dimension values must be between 1 and 1")
+	return dX, nil
+}
+
+// ParseIsometricSkewbParams парсит параметры для изометрической SVG картинки скьюба
+func ParseIsometricSkewbParams(pDimensions, pColors string) (IsometricSkewb, error) {
+
+	// Извлечение размеров из строки pDimensions
+	order, err := parseSkewbDimensions(pDimensions)
+	if err != nil {
+		return IsometricSkewb{}, err
 	}
 
 	// Инициализация структуры IsometricSkewb
 	skewb := IsometricSkewb{
+		Order:  order,
 		Colors: make(map[Side][]rune),
 	}
 
@@ -49,9 +69,10 @@ func ParseIsometricSkewbParams(pDimensions, pColors string) (IsometricSkewb, err
 	}
 
 	// Парсинг цветов для каждой стороны
-	skewb.Colors[Front] = stringToRuneGrid(getColorOrEmpty(0, "X"), 5, 1)[0]
-	skewb.Colors[Up] = stringToRuneGrid(getColorOrEmpty(1, "X"), 5, 1)[0]
-	skewb.Colors[Right] = stringToRuneGrid(getColorOrEmpty(2, "X"), 5, 1)[0]
+	count := skewbStickerCount(order)
+	skewb.Colors[Front] = stringToRuneGrid(getColorOrEmpty(0, "X"), count, 1)[0]
+	skewb.Colors[Up] = stringToRuneGrid(getColorOrEmpty(1, "X"), count, 1)[0]
+	skewb.Colors[Right] = stringToRuneGrid(getColorOrEmpty(2, "X"), count, 1)[0]
 
 	// Цвет фона (base) будет последним в массиве Colors
 	skewb.Colors[Base] = stringToRuneGrid(getColorOrEmpty(3, "K"), 1, 1)[0]
@@ -65,41 +86,53 @@ func GenerateIsometricSkewb(skewb IsometricSkewb) string {
 
 	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
 
-	// Определяем элементы на сторонах (side) скьюба с размерами
+	// С каждым порядком картинка растёт на половину картинки обычного скьюба
+	scale := float64(skewb.order()+1) / 2
+	width, height := 172.57*scale, 194.62*scale
+
+	// Вершины шестиугольника скьюба: верхняя, правые, нижняя, левые и центр
+	r := 99.65 * scale
+	center := Point{X: width / 2, Y: height / 2}
+	top := Point{X: center.X, Y: center.Y - r}
+	upRight := Point{X: width, Y: center.Y - r/2}
+	downRight := Point{X: width, Y: center.Y + r/2}
+	bottom := Point{X: center.X, Y: center.Y + r}
+	downLeft := Point{X: 0, Y: center.Y + r/2}
+	upLeft := Point{X: 0, Y: center.Y - r/2}
+
+	// Ромбы сторон: левый верхний угол развёртки стороны и направления её осей.
+	// Сторона U нарисована, как на развёртке: задней стороной вверх
+	type rhombus struct{ Origin, AxisX, AxisY Point }
+	axis := func(from, to Point) Point { return Point{X: to.X - from.X, Y: to.Y - from.Y} }
+	rhombi := map[Side]rhombus{
+		Front: {Origin: upLeft, AxisX: axis(upLeft, center), AxisY: axis(upLeft, downLeft)},
+		Up:    {Origin: top, AxisX: axis(top, upRight), AxisY: axis(top, upLeft)},
+		Right: {Origin: center, AxisX: axis(center, upRight), AxisY: axis(center, bottom)},
+	}
+
+	// Определяем элементы на сторонах (side) скьюба: наклейки развёртки, перенесённые на ромбы
 	skewb.SideParams = map[Side]IsometricSkewbSide{
-		Front: {
-			Drawn: [5]string{
-				"m18.5 84.3-8.5 4.9c-3.5 2-6.1-.5-6.1-4.6v-25.6c0-3.7 3-4.9 5.7-3.4l23.6 13.6c2.6 1.6 2.2 5.2-1.3 7.3z",
-				"m83.6 105c0-3.7-2.2-8-5-9.6l-22.5-13c-4-2.3-6 1-3.9 4.7l25.4 43.9c2.1 3.7 6 3.7 6-.1v-25.9z",
-				"m8.9 109.8c-1.8-3.1-4.9-3-4.9.7v27.1c0 3.7 2.2 8 5 9.6l23.1 13.4c2.8 1.6 6-.1 4.2-3.3z",
-				"m83.6 154v29.7c0 3.7-2.3 5.4-5 3.8l-24-13.9c-2.8-1.6-1.7-4.6.6-5.9l23.2-13.4c2.1-1.2 5.2-.4 5.2 3.2z",
-				"m80 141.5-33.5-58.1c-2.7-4.7-8.6-4.3-11.2-2.8l-23.5 13.6c-2.8 1.6-4.5 7-2.6 10.3l33.7 58.3c1.2 2.1 5 3.3 6.8 2.2l27.8-16c3.9-2.3 3.3-6.1 2.5-7.4z"},
-		},
-		Up: {
-			Drawn: [5]string{
-				"m41.1 62.3-0-26c0-4.3-3.4-5.9-6-4.4l-23.5 13.6c-2.8 1.6-2.4 4.4.8 6.2l22.9 13.2c3.2 1.8 5.8 1.1 5.8-2.6z",
-				"m115.8 23.7c2.6 0 4-2.1.8-3.9l-24.6-14.2c-3.2-1.8-8-2-10.8-.5l-25.3 14.6c-2.6 1.5-1.1 3.9 1.3 3.9z",
-				"m91.3 91.5 24.4-14.3c2.8-1.6 1.4-4.7-1.9-4.7l-55.3 0c-2.8 0-3.9 3.4-1.7 4.7l23.9 13.8c3.2 1.8 8 2 10.6.5z",
-				"m135.9 65.6 25.1-14.4c2.8-1.6 2.4-4.4-.8-6.2l-23.6-13.7c-2.2-1.2-5.2-1.2-5.2 2.1l0 29.4c0 3.2 3.1 3.7 4.5 2.8z",
-				"m126.2 62.7-.1-30.4c0-3.5-3-5.6-6.9-5.6l-66.1 0c-3.6 0-6.8 2.5-6.8 5.6l0 30.4c0 3.2 2.9 5.6 6.8 5.6l66.2 0c3.6 0 6.8-2.5 6.8-5.6z"},
-		},
-		Right: {
-			Drawn: [5]string{
-				"m89 106-0 25c-0 3.7 4.3 3.7 6.4-.1l25.3-43.9c1.8-3.2.2-6.2-2.5-4.6l-24.2 14c-2.8 1.6-5 5.9-5 9.6z",
-				"m139.7 76.3 21.7 12.4c4.2 2.4 7.3.7 7.3-3v-25.7c0-3.7-2.3-5.4-5-3.8l-24.1 13.9c-2.8 1.6-3.1 4.4.1 6.2z",
-				"m89 157.5v27.2c0 3.7 2.3 5.4 5 3.8l25.8-14.9c2.4-1.4 1.6-4.5-.8-5.9l-23.1-13.3c-2.1-1.2-6.9-.4-6.9 3.2z",
-				"m136.9 156.9c-1.8 3.2 2.2 5.5 5 3.9l21.8-12.6c2.8-1.6 5-5.9 5-9.6v-28c0-3.7-2.7-4.1-4.7-.6z",
-				"m93 141.4 33.5-58.1c2.7-4.7 8.6-4.3 11.2-2.8l23.5 13.6c2.8 1.6 4.5 7 2.6 10.3l-33.7 58.3c-1.2 2.1-5 3.3-6.8 2.2l-27.8-16c-3.9-2.3-3.3-6.1-2.5-7.4z"},
-		},
-		Base: {
-			Drawn: [5]string{"M172.57 138.48v-82.33a15 15 0 00-7.49-13l-71.31 -41.17a14.94 14.94 0 00-15 0l-71.28 41.17a15 15 0 00-7.49 13v82.33a15 15 0 007.49 13l71.31 41.17a15 15 0 0015 0l71.28 -41.17a15 15 0 007.49-13z"},
-		},
+		Base: {Drawn: []string{roundedPolygonPath([]Point{top, upRight, downRight, bottom, downLeft, upLeft}, 15)}},
+	}
+	for side, rh := range rhombi {
+		var drawn []string
+		for _, cell := range skewbFaceCells(side, skewb.order()) {
+			polygon := make([]Point, len(cell))
+			for i, p := range cell {
+				polygon[i] = Point{
+					X: rh.Origin.X + rh.AxisX.X*p.X + rh.AxisY.X*p.Y,
+					Y: rh.Origin.Y + rh.AxisX.Y*p.X + rh.AxisY.Y*p.Y,
+				}
+			}
+			drawn = append(drawn, roundedStickerPath(polygon, 3.5, 6))
+		}
+		skewb.SideParams[side] = IsometricSkewbSide{Drawn: drawn}
 	}
 
 	// // // // // СТРОИМ SVG
 
 	// Создаём рамку (viewBox)
-	builder.WriteString("<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 172.57 194.62\">")
+	GenerateViewBox(&builder, width, height, skewb.Rotate)
 
 	// Создаём основу (base)
 	colorBase := skewb.Colors[Base][0]
@@ -112,12 +145,13 @@ func GenerateIsometricSkewb(skewb IsometricSkewb) string {
 	GenerateIsometricSkewbSide(&builder, skewb, Right)
 
 	// Закрываем рамку (viewBox)
-	builder.WriteString("\r\n</svg>")
+	CloseViewBox(&builder, skewb.Rotate)
 
 	// Возвращаем сгенерированную SVG
 	return builder.String()
 }
 
+// GenerateIsometricSkewbSide рисует наклейки одной стороны изометрического скьюба
 func GenerateIsometricSkewbSide(builder *strings.Builder, skewb IsometricSkewb, side Side) {
 	sideParam := skewb.SideParams[side]
 
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SkewbMove хранит один ход скьюба: поворот половины вокруг угла или поворот всего скьюба
+type SkewbMove struct {
+	Name     string // Исходная запись хода
+	Corner   Vec3   // Угол, вокруг которого поворачивается половина скьюба
+	Rotation bool   // Поворот всего скьюба вокруг оси Axis
+	Axis     Axis   // Ось поворота всего скьюба
+	Turns    int    // Число поворотов по часовой стрелке: на треть оборота для углов (1..2), на четверть для осей (1..3)
+}
+
+// Углы ходов в нотации WCA: каждый ход поворачивает половину скьюба вокруг неподвижного угла
+var skewbMoveCorners = map[rune]Vec3{
+	'R': {X: 1, Y: -1, Z: -1},  // DRB
+	'L': {X: -1, Y: -1, Z: 1},  // DLF
+	'U': {X: -1, Y: 1, Z: -1},  // ULB
+	'B': {X: -1, Y: -1, Z: -1}, // DLB
+}
+
+// Оси поворотов всего скьюба (как у кубика)
+var skewbRotationAxes = map[rune]Axis{
+	'x': AxisX,
+	'y': AxisY,
+	'z': AxisZ,
+}
+
+// Регулярное выражение для одного хода скьюба: буква[число][']
+var skewbMoveRegexp = regexp.MustCompile(`^([RLUBxyz])(\d+)?(['’]?)`)
+
+// ParseSkewbAlgorithm разбирает алгоритм скьюба в нотации WCA (R, U, L, B) с поворотами x, y, z
+func ParseSkewbAlgorithm(alg string) ([]SkewbMove, error) {
+	var moves []SkewbMove
+
+	rest := alg
+	for {
+		// Пропускаем пробелы и скобки, они не влияют на ходы
+		rest = strings.TrimLeft(rest, " \t\r\n()[]+_")
+		if rest == "" {
+			break
+		}
+
+		m := skewbMoveRegexp.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, fmt.Errorf("invalid move notation near %q", rest)
+		}
+		rest = rest[len(m[0]):]
+
+		letter := []rune(m[1])[0]
+		move := SkewbMove{Name: m[0]}
+
+		// Количество поворотов по часовой стрелке
+		amount := 1
+		if m[2] != "" {
+			amount, _ = strconv.Atoi(m[2])
+		}
+		if m[3] != "" {
+			amount = -amount
+		}
+
+		if axis, ok := skewbRotationAxes[letter]; ok {
+			move.Rotation, move.Axis = true, axis
+			move.Turns = (amount%4 + 4) % 4
+		} else {
+			move.Corner = skewbMoveCorners[letter]
+			move.Turns = (amount%3 + 3) % 3
+		}
+
+		if move.Turns != 0 {
+			moves = append(moves, move)
+		}
+	}
+
+	return moves, nil
+}
+
+// InvertSkewbAlgorithm возвращает обратную последовательность ходов скьюба
+func InvertSkewbAlgorithm(moves []SkewbMove) []SkewbMove {
+	inverse := make([]SkewbMove, len(moves))
+	for i, move := range moves {
+		if move.Rotation {
+			move.Turns = (4 - move.Turns) % 4
+		} else {
+			move.Turns = (3 - move.Turns) % 3
+		}
+		inverse[len(moves)-1-i] = move
+	}
+	return inverse
+}
+
+// rotateCorner поворачивает вектор на треть оборота по часовой стрелке,
+// если смотреть на угол corner снаружи. Для угла (1, 1, 1) это циклическая перестановка
+// координат; для остальных углов она переносится отражением осей, которое при нечётном
+// числе отражений меняет направление поворота
+func (v Vec3) rotateCorner(corner Vec3) Vec3 {
+	w := Vec3{X: v.X * corner.X, Y: v.Y * corner.Y, Z: v.Z * corner.Z}
+	if corner.X*corner.Y*corner.Z > 0 {
+		w = Vec3{X: w.Y, Y: w.Z, Z: w.X}
+	} else {
+		w = Vec3{X: w.Z, Y: w.X, Z: w.Y}
+	}
+	return Vec3{X: w.X * corner.X, Y: w.Y * corner.Y, Z: w.Z * corner.Z}
+}
+
+// ApplyMoves применяет ходы к состоянию скьюба. Вместе с углом поворачиваются
+// детали, лежащие по его сторону от разреза: сам угол, три соседних угла и три центра
+func (s *SkewbState) ApplyMoves(moves []SkewbMove) {
+	for _, move := range moves {
+		for i := range s.Stickers {
+			st := &s.Stickers[i]
+			if !move.Rotation && st.Pos.X*move.Corner.X+st.Pos.Y*move.Corner.Y+st.Pos.Z*move.Corner.Z <= 0 {
+				continue
+			}
+			for t := 0; t < move.Turns; t++ {
+				if move.Rotation {
+					st.Pos = st.Pos.rotate(move.Axis)
+					st.Normal = st.Normal.rotate(move.Axis)
+				} else {
+					st.Pos = st.Pos.rotateCorner(move.Corner)
+					st.Normal = st.Normal.rotateCorner(move.Corner)
+				}
+			}
+		}
+	}
+}
+
+// ApplyAlgorithm разбирает алгоритм и применяет его к состоянию скьюба
+func (s *SkewbState) ApplyAlgorithm(alg string) error {
+	moves, err := ParseSkewbAlgorithm(alg)
+	if err != nil {
+		return err
+	}
+	s.ApplyMoves(moves)
+	return nil
+}
+
+// ApplyCase применяет к состоянию скьюба обратный алгоритм,
+// чтобы получить случай, который этот алгоритм решает
+func (s *SkewbState) ApplyCase(alg string) error {
+	moves, err := ParseSkewbAlgorithm(alg)
+	if err != nil {
+		return err
+	}
+	s.ApplyMoves(InvertSkewbAlgorithm(moves))
+	return nil
+}
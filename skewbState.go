@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SkewbState хранит полное состояние наклеек скьюба: по пять на каждой из шести сторон.
+// Угловая наклейка лежит на углу Pos (координаты ±1, как центры кубиков 2x2x2),
+// у центральной наклейки Pos совпадает с нормалью стороны
+type SkewbState struct {
+	Stickers []Sticker // Все наклейки всех шести сторон
+	Base     rune      // Цвет основы (base)
+}
+
+// Углы скьюба совпадают с центрами угловых кубиков 2x2x2
+var skewbCorners = &CubeState{Size: Size{X: 2, Y: 2, Z: 2}}
+
+// skewbStickerPos возвращает положение наклейки i (0–3 — углы, 4 — центр) стороны side
+func skewbStickerPos(side Side, i int) Vec3 {
+	if i == 4 {
+		return sideNormals[side]
+	}
+	corner := skewbStickerCorners[side][i]
+	return skewbCorners.stickerPos(side, corner/2, corner%2)
+}
+
+// NewSkewbState создаёт состояние скьюба из цветов сторон (по пять наклеек)
+func NewSkewbState(colors map[Side][]rune, base rune) *SkewbState {
+	state := &SkewbState{Base: base}
+	for _, side := range stateSides {
+		for i, color := range colors[side] {
+			pos := skewbStickerPos(side, i)
+			state.Stickers = append(state.Stickers, Sticker{
+				Pos:          pos,
+				Normal:       sideNormals[side],
+				Color:        color,
+				Origin:       pos,
+				OriginNormal: sideNormals[side],
+			})
+		}
+	}
+	return state
+}
+
+// ParseSkewbStateParams парсит размер и цвета собранного скьюба для построения состояния.
+// Цвета указываются в порядке развёртки: {front}-{left}-{up}-{right}-{down}-{back}-{base}
+func ParseSkewbStateParams(pDimensions, pColors string) (*SkewbState, error) {
+
+	// Извлечение размеров из строки pDimensions; ходы поддерживаются только у обычного скьюба
+	order, err := parseSkewbDimensions(pDimensions)
+	if err != nil {
+		return nil, err
+	}
+	if order != 1 {
+		return nil, fmt.Errorf("moves are supported only for the order 1 skewb")
+	}
+
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrDefault := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	colors := make(map[Side][]rune)
+	for i, side := range stateSides {
+		colors[side] = stringToRuneGrid(getColorOrDefault(i, defaultSchemeColors[side]), 5, 1)[0]
+	}
+	base := stringToRuneGrid(getColorOrDefault(6, "K"), 1, 1)[0][0]
+
+	return NewSkewbState(colors, base), nil
+}
+
+// Faces возвращает цвета всех сторон в порядке наклеек скьюба
+func (s *SkewbState) Faces() map[Side][]rune {
+	faces := make(map[Side][]rune)
+	for _, side := range stateSides {
+		faces[side] = make([]rune, 5)
+	}
+	for _, st := range s.Stickers {
+		for _, side := range stateSides {
+			if sideNormals[side] != st.Normal {
+				continue
+			}
+			for i := 0; i < 5; i++ {
+				if skewbStickerPos(side, i) == st.Pos {
+					faces[side][i] = st.Color
+				}
+			}
+		}
+	}
+	faces[Base] = []rune{s.Base}
+	return faces
+}
+
+// Unfolded возвращает развёртку скьюба для GenerateUnfoldedSkewb
+func (s *SkewbState) Unfolded() IsometricSkewb {
+	return IsometricSkewb{Colors: s.Faces()}
+}
+
+// Isometric возвращает изометрический вид скьюба для GenerateIsometricSkewb
+func (s *SkewbState) Isometric() IsometricSkewb {
+	faces := s.Faces()
+	return IsometricSkewb{
+		Colors: map[Side][]rune{
+			Front: faces[Front],
+			Up:    faces[Up],
+			Right: faces[Right],
+			Base:  faces[Base],
+		},
+	}
+}
+
+// Flat возвращает вид сверху для GenerateFlatSkewb: сторону U и полоски соседних сторон
+// (угол, центр, угол вдоль ребра с U) слева направо или сверху вниз, если смотреть сверху
+func (s *SkewbState) Flat() IsometricSkewb {
+	faces := s.Faces()
+	skewb := IsometricSkewb{Colors: map[Side][]rune{Up: faces[Up], Base: faces[Base]}}
+
+	for _, side := range []Side{Left, Back, Right, Front} {
+		var corners []Sticker
+		for _, st := range s.Stickers {
+			if st.Normal == sideNormals[side] && st.Pos != st.Normal && st.Pos.Y == 1 {
+				corners = append(corners, st)
+			}
+		}
+
+		// На виде сверху X идёт слева направо, Z — сверху вниз
+		sort.Slice(corners, func(i, j int) bool {
+			if side == Left || side == Right {
+				return corners[i].Pos.Z < corners[j].Pos.Z
+			}
+			return corners[i].Pos.X < corners[j].Pos.X
+		})
+		skewb.Colors[side] = []rune{corners[0].Color, faces[side][4], corners[1].Color}
+	}
+
+	return skewb
+}
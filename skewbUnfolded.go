@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseUnfoldedSkewbParams парсит параметры для развёртки SVG картинки скьюба
+func ParseUnfoldedSkewbParams(pDimensions, pColors string) (IsometricSkewb, error) {
+
+	// Извлечение размеров из строки pDimensions
+	order, err := parseSkewbDimensions(pDimensions)
+	if err != nil {
+		return IsometricSkewb{}, err
+	}
+
+	// Инициализация структуры IsometricSkewb
+	skewb := IsometricSkewb{
+		Order:  order,
+		Colors: make(map[Side][]rune),
+	}
+
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+
+	// Функция для безопасного извлечения цвета или возвращения цвета по умолчанию
+	getColorOrEmpty := func(index int, defaultColor string) string {
+		if index < len(Colors) && len(Colors[index]) > 0 {
+			return Colors[index]
+		}
+		return defaultColor
+	}
+
+	// Парсинг цветов для каждой стороны в порядке развёртки кубика
+	for i, side := range stateSides {
+		skewb.Colors[side] = stringToRuneGrid(getColorOrEmpty(i, "X"), skewbStickerCount(order), 1)[0]
+	}
+
+	// Цвет фона (base) будет последним в массиве Colors
+	skewb.Colors[Base] = stringToRuneGrid(getColorOrEmpty(6, "K"), 1, 1)[0]
+
+	return skewb, nil
+}
+
+// GenerateUnfoldedSkewb генерирует развёртку SVG картинку скьюба крестом, как GenerateUnfoldedCube
+func GenerateUnfoldedSkewb(skewb IsometricSkewb) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Длина стороны вместе с основой; соседние стороны перекрываются на 7 точек
+	l := 8.0 + skewbFaceSize(skewb.order())
+	origins, viewBoxSize, outline := unfoldedCubeLayout(l)
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, skewb.Rotate)
+
+	// Создаём основу (base)
+	colorBase := skewb.Colors[Base][0]
+	builder.WriteString(fmt.Sprintf("\r\n\t<path id=\"base\" d=\"%s\" style=\"fill: %s\"/>",
+		roundedPolygonPath(outline, skewbBase), colorMapRGBA[colorBase]))
+
+	// Создаём стороны (side)
+	for _, side := range stateSides {
+		origin := origins[side]
+		generateFlatSkewbFace(&builder, skewb, side, Point{X: origin.X + 4, Y: origin.Y + 4})
+	}
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, skewb.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
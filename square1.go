@@ -0,0 +1,515 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Square1Piece деталь слоя Square-1
+type Square1Piece struct {
+	Corner bool   // Угол (60°) или ребро (30°)
+	Color  rune   // Цвет наклейки на верхней (нижней) стороне
+	Sides  []rune // Цвета боковых наклеек по часовой стрелке, если смотреть снаружи на верх (низ) детали
+}
+
+type Square1 struct {
+	Top     []Square1Piece // Детали верхнего слоя по часовой стрелке сверху, начиная от среза справа сзади
+	Bottom  []Square1Piece // Детали нижнего слоя по часовой стрелке снизу, начиная от среза спереди
+	Flipped bool           // Правая половина среднего слоя перевёрнута
+	Colors  map[Side]rune  // Цвета сторон
+	Rotate  float64        // Угол поворота картинки в градусах
+}
+
+// square1PieceSides стороны, на которых лежат наклейки деталей собранной головоломки:
+// первая — верх или низ, остальные — боковые по часовой стрелке снаружи
+var square1PieceSides = map[rune][]Side{
+	'A': {Up, Back, Right},
+	'1': {Up, Right},
+	'B': {Up, Right, Front},
+	'2': {Up, Front},
+	'C': {Up, Front, Left},
+	'3': {Up, Left},
+	'D': {Up, Left, Back},
+	'4': {Up, Back},
+	'5': {Down, Front},
+	'E': {Down, Front, Right},
+	'6': {Down, Right},
+	'F': {Down, Right, Back},
+	'7': {Down, Back},
+	'G': {Down, Back, Left},
+	'8': {Down, Left},
+	'H': {Down, Left, Front},
+}
+
+// Формы слоёв из серых деталей (X — угол, 0 — ребро): сверху по часовой стрелке от среза
+// для верхнего слоя и снизу от среза для нижнего. Слой в форме квадрата у нижнего слоя
+// начинается с ребра, как в собранной головоломке
+var square1Shapes = map[string][2]string{
+	"square":   {"X0X0X0X0", "0X0X0X0X"},
+	"kite":     {"XX000XX0", "XX000XX0"},
+	"barrel":   {"XX00XX00", "XX00XX00"},
+	"shield":   {"XXX00X00", "XXX00X00"},
+	"mushroom": {"XXXX0000", "XXXX0000"},
+	"scallop":  {"X0X00X0X", "X0X00X0X"},
+	"star":     {"XXXXXX", "XXXXXX"},
+}
+
+// Параметры построения Square-1 (в долях половины стороны квадрата и в точках)
+const (
+	square1Scale  = 80  // Половина стороны квадратного слоя
+	square1Gap    = 3   // Отступ наклейки от границ детали
+	square1Round  = 5   // Радиус скругления углов наклейки
+	square1Border = 4   // Выступ основы за края деталей
+	square1Top    = 0.7 // Толщина верхнего и нижнего слоёв
+	square1Middle = 0.6 // Толщина среднего слоя
+)
+
+// Углы среза (по часовой стрелке от направления назад, если смотреть сверху)
+const (
+	square1SliceBack  = 15.0
+	square1SliceFront = 195.0
+)
+
+// ParseSquare1Params парсит состояние Square-1 вида {top}-{bottom}-{middle}
+// и цвета сторон вида {front}-{left}-{up}-{right}-{down}-{back}-{base}
+func ParseSquare1Params(pState, pColors string) (Square1, error) {
+	// Цвета сторон (по одной букве)
+	Colors := strings.Split(strings.ToUpper(pColors), "-")
+	square1 := Square1{Colors: make(map[Side]rune)}
+	for i, side := range append(stateSides[:], Base) {
+		color := 'K'
+		if side != Base {
+			color = rune(defaultSchemeColors[side][0])
+		}
+		if i < len(Colors) && len(Colors[i]) > 0 {
+			color = rune(Colors[i][0])
+		}
+		square1.Colors[side] = color
+	}
+
+	// Извлечение слоёв и среднего слоя
+	// Одно название формы задаёт оба слоя, cubeshape — форма куба
+	parts := strings.Split(pState, "-")
+	if len(parts) == 1 {
+		name := strings.ToLower(parts[0])
+		if name == "cubeshape" {
+			name = "square"
+		}
+		if _, ok := square1Shapes[name]; ok {
+			parts = []string{name, name}
+		}
+	}
+	if len(parts) != 2 && len(parts) != 3 {
+		return Square1{}, fmt.Errorf("invalid state: expected {top}-{bottom}-{middle}")
+	}
+	if len(parts) == 3 {
+		switch strings.ToLower(parts[2]) {
+		case "s":
+			square1.Flipped = false
+		case "f":
+			square1.Flipped = true
+		default:
+			return Square1{}, fmt.Errorf("invalid middle layer %q: expected s (square) or f (flipped)", parts[2])
+		}
+	}
+
+	used := make(map[rune]bool)
+	var err error
+	if square1.Top, err = square1.parseLayer("top", 0, parts[0], used); err != nil {
+		return Square1{}, err
+	}
+	if square1.Bottom, err = square1.parseLayer("bottom", 1, parts[1], used); err != nil {
+		return Square1{}, err
+	}
+
+	return square1, nil
+}
+
+// parseLayer парсит детали слоя layer (0 — верхний, 1 — нижний) и проверяет, что слой занимает
+// ровно 12 делений по 30°. Углы A–H и рёбра 1–8 — детали собранной головоломки, X и 0 — серые
+// угол и ребро (только форма). Вместо деталей можно указать название формы слоя из square1Shapes
+func (square1 Square1) parseLayer(name string, layer int, pLayer string, used map[rune]bool) ([]Square1Piece, error) {
+	if shape, ok := square1Shapes[strings.ToLower(pLayer)]; ok {
+		pLayer = shape[layer]
+	}
+
+	var pieces []Square1Piece
+	units := 0
+	for _, r := range strings.ToUpper(pLayer) {
+		var piece Square1Piece
+		switch r {
+		case 'X':
+			piece = Square1Piece{Corner: true, Color: 'X', Sides: []rune{'X', 'X'}}
+		case '0':
+			piece = Square1Piece{Corner: false, Color: 'X', Sides: []rune{'X'}}
+		default:
+			sides, ok := square1PieceSides[r]
+			if !ok {
+				return nil, fmt.Errorf("invalid %s layer: unknown piece %q", name, r)
+			}
+			if used[r] {
+				return nil, fmt.Errorf("invalid %s layer: piece %q is used twice", name, r)
+			}
+			used[r] = true
+
+			piece = Square1Piece{Corner: len(sides) == 3, Color: square1.Colors[sides[0]]}
+			for _, side := range sides[1:] {
+				piece.Sides = append(piece.Sides, square1.Colors[side])
+			}
+		}
+
+		if piece.Corner {
+			units += 2
+		} else {
+			units++
+		}
+		pieces = append(pieces, piece)
+	}
+
+	if units != 12 {
+		return nil, fmt.Errorf("invalid %s layer: pieces take %d units of 30°, expected 12", name, units)
+	}
+	return pieces, nil
+}
+
+// square1Edge боковая грань детали на виде сверху (отрезок контура) с цветом наклейки
+type square1Edge struct {
+	From, To Point
+	Color    rune
+}
+
+// square1Facet деталь на виде сверху: контур в координатах (x вправо, z вперёд),
+// цвет верхней наклейки (0 — без наклейки) и боковые грани
+type square1Facet struct {
+	Outline []Point
+	Color   rune
+	Edges   []square1Edge
+}
+
+// square1Point возвращает точку на луче под углом angle (по часовой стрелке от направления назад)
+func square1Point(angle, radius float64) Point {
+	a := angle * math.Pi / 180
+	return Point{X: radius * math.Sin(a), Y: -radius * math.Cos(a)}
+}
+
+// square1Layer строит детали слоя на виде сверху, начиная от угла start в направлении dir
+// (1 — по часовой стрелке, -1 — против, если смотреть сверху)
+func square1Layer(pieces []Square1Piece, start, dir float64) []square1Facet {
+	edgeRadius := 1 / math.Cos(math.Pi/12)
+	cornerRadius := math.Sqrt2
+
+	var facets []square1Facet
+	angle := start
+	for _, piece := range pieces {
+		if piece.Corner {
+			p0 := square1Point(angle, edgeRadius)
+			p1 := square1Point(angle+dir*30, cornerRadius)
+			p2 := square1Point(angle+dir*60, edgeRadius)
+			facets = append(facets, square1Facet{
+				Outline: []Point{{}, p0, p1, p2},
+				Color:   piece.Color,
+				Edges:   []square1Edge{{From: p0, To: p1, Color: piece.Sides[0]}, {From: p1, To: p2, Color: piece.Sides[1]}},
+			})
+			angle += dir * 60
+		} else {
+			p0 := square1Point(angle, edgeRadius)
+			p1 := square1Point(angle+dir*30, edgeRadius)
+			facets = append(facets, square1Facet{
+				Outline: []Point{{}, p0, p1},
+				Color:   piece.Color,
+				Edges:   []square1Edge{{From: p0, To: p1, Color: piece.Sides[0]}},
+			})
+			angle += dir * 30
+		}
+	}
+	return facets
+}
+
+// square1MiddleLayer строит половины среднего слоя на виде сверху. Срез делит квадрат на левую
+// и правую половины; перевёрнутая правая половина отражается вдоль линии среза
+func square1MiddleLayer(square1 Square1) []square1Facet {
+	back := square1Point(square1SliceBack, 1/math.Cos(math.Pi/12))
+	front := square1Point(square1SliceFront, 1/math.Cos(math.Pi/12))
+
+	// Углы квадрата и цвета сторон, которые идут после них по часовой стрелке
+	c := square1.Colors
+	right := []Point{back, {X: 1, Y: -1}, {X: 1, Y: 1}, front}
+	rightColors := []rune{c[Back], c[Right], c[Front]}
+	left := []Point{front, {X: -1, Y: 1}, {X: -1, Y: -1}, back}
+	leftColors := []rune{c[Front], c[Left], c[Back]}
+
+	half := func(points []Point, colors []rune) square1Facet {
+		facet := square1Facet{Outline: append([]Point{}, points...)}
+		for i, color := range colors {
+			facet.Edges = append(facet.Edges, square1Edge{From: points[i], To: points[i+1], Color: color})
+		}
+		return facet
+	}
+
+	if square1.Flipped {
+		// Отражение вдоль линии среза: составляющая вдоль среза меняет знак
+		d := Point{X: back.X - front.X, Y: back.Y - front.Y}
+		length := math.Hypot(d.X, d.Y)
+		d = Point{X: d.X / length, Y: d.Y / length}
+		mirror := func(p Point) Point {
+			s := p.X*d.X + p.Y*d.Y
+			return Point{X: p.X - 2*s*d.X, Y: p.Y - 2*s*d.Y}
+		}
+		// Порядок обхода сохраняется обратным проходом
+		mirrored := make([]Point, len(right))
+		for i, p := range right {
+			mirrored[len(right)-1-i] = mirror(p)
+		}
+		right = mirrored
+		rightColors = []rune{rightColors[2], rightColors[1], rightColors[0]}
+	}
+
+	return []square1Facet{half(left, leftColors), half(right, rightColors)}
+}
+
+// GenerateFlatSquare1 генерирует SVG картинку слоёв Square-1 сверху: верхний слой слева,
+// нижний слой справа (вид снизу, перевёрнутый вокруг оси вперёд–назад), средний слой под ними
+func GenerateFlatSquare1(square1 Square1) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	// Нижний слой при взгляде снизу отражается слева направо, поэтому строится
+	// по часовой стрелке от отражённого угла среза
+	top := square1Layer(square1.Top, square1SliceBack, 1)
+	bottom := square1Layer(square1.Bottom, 360-square1SliceFront, 1)
+	middle := square1MiddleLayer(square1)
+
+	// Расположение: слои рядом, средний слой вдвое меньше под ними
+	const spacing = 3.2
+	place := func(facets []square1Facet, center Point, scale float64) []square1Facet {
+		moved := make([]square1Facet, len(facets))
+		move := func(p Point) Point {
+			return Point{X: (center.X + p.X*scale) * square1Scale, Y: (center.Y + p.Y*scale) * square1Scale}
+		}
+		for i, f := range facets {
+			moved[i] = square1Facet{Color: f.Color}
+			for _, p := range f.Outline {
+				moved[i].Outline = append(moved[i].Outline, move(p))
+			}
+			for _, e := range f.Edges {
+				moved[i].Edges = append(moved[i].Edges, square1Edge{From: move(e.From), To: move(e.To), Color: e.Color})
+			}
+		}
+		return moved
+	}
+	layers := [][]square1Facet{
+		place(top, Point{}, 1),
+		place(bottom, Point{X: spacing}, 1),
+		place(middle, Point{X: spacing / 2, Y: 2.4}, 0.5),
+	}
+
+	// Считаем размер рамки (viewBox): полоски боковых наклеек выступают на 7 точек
+	var outlines [][]Point
+	for _, layer := range layers {
+		for _, f := range layer {
+			outlines = append(outlines, f.Outline)
+		}
+	}
+	offset, viewBoxSize := fitOutlines(outlines, 8)
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, square1.Rotate)
+	builder.WriteString(fmt.Sprintf("\r\n<g transform=\"translate(%.2f %.2f)\">", offset.X, offset.Y))
+
+	for i, name := range []string{"top", "bottom", "middle"} {
+		facets := layers[i]
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", name))
+
+		// Основа (base)
+		colorBase := colorMapRGBA[square1.Colors[Base]]
+		for _, f := range facets {
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"%s\" style=\"fill: %s; stroke: %s; stroke-width: %d; stroke-linejoin: round\"/>",
+				polygonPath(f.Outline), colorBase, colorBase, 2*square1Border))
+		}
+
+		// Наклейки сверху
+		for _, f := range facets {
+			if f.Color == 0 {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"%s\" style=\"fill: %s\"/>",
+				roundedPolygonPath(insetPolygon(f.Outline, square1Gap), square1Round), colorMapRGBA[f.Color]))
+		}
+
+		// Полоски боковых наклеек снаружи контура
+		for _, f := range facets {
+			center := square1Centroid(f.Outline)
+			for _, e := range f.Edges {
+				builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"%s\" style=\"fill: %s\"/>",
+					roundedPolygonPath(square1Strip(e, center, 1, 7), 2), colorMapRGBA[e.Color]))
+			}
+		}
+
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	builder.WriteString("\r\n</g>")
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, square1.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
+
+// square1Strip возвращает полоску боковой наклейки вдоль грани e на расстоянии от h0 до h1
+// снаружи детали (с противоположной от точки center стороны)
+func square1Strip(e square1Edge, center Point, h0, h1 float64) []Point {
+	dx, dy := e.To.X-e.From.X, e.To.Y-e.From.Y
+	length := math.Hypot(dx, dy)
+	ux, uy := dx/length, dy/length
+	nx, ny := -uy, ux
+	if nx*(e.From.X-center.X)+ny*(e.From.Y-center.Y) < 0 {
+		nx, ny = -nx, -ny
+	}
+
+	at := func(t, h float64) Point {
+		return Point{X: e.From.X + ux*t + nx*h, Y: e.From.Y + uy*t + ny*h}
+	}
+	return []Point{at(square1Gap, h0), at(length-square1Gap, h0), at(length-square1Gap, h1), at(square1Gap, h1)}
+}
+
+// square1Centroid возвращает среднюю точку вершин многоугольника
+func square1Centroid(points []Point) Point {
+	c := Point{}
+	for _, p := range points {
+		c = Point{X: c.X + p.X/float64(len(points)), Y: c.Y + p.Y/float64(len(points))}
+	}
+	return c
+}
+
+// Камера изометрического вида Square-1
+var square1Camera = Camera{Yaw: 45, Pitch: 35.26}
+
+// square1Face видимая грань детали на изометрическом виде
+type square1Face struct {
+	Depth   float64 // Удалённость от камеры
+	Outline []Point // Контур грани основы
+	Sticker []Point // Контур наклейки (nil — грань без наклейки)
+	Color   rune    // Цвет наклейки
+}
+
+// GenerateIsometricSquare1 генерирует изометрическую SVG картинку Square-1:
+// видны верхний слой, а также передняя и правая стороны всех трёх слоёв
+func GenerateIsometricSquare1(square1 Square1) string {
+	var builder strings.Builder
+
+	// // // // // ПРОИЗВОДИМ РАСЧЁТЫ
+
+	view := newCameraView(square1Camera, 0)
+	project := func(p Point3) Point {
+		r := view.rotate(p)
+		return Point{X: r.X * square1Scale, Y: -r.Y * square1Scale}
+	}
+	gap := square1Gap / float64(square1Scale)
+
+	// Слои снизу вверх: нижний слой не может закрыть верхний, если камера смотрит сверху
+	top := square1Top + square1Middle/2
+	layers := []struct {
+		Facets []square1Facet
+		Y0, Y1 float64
+	}{
+		{square1Layer(square1.Bottom, square1SliceFront, -1), -top, -square1Middle / 2},
+		{square1MiddleLayer(square1), -square1Middle / 2, square1Middle / 2},
+		{square1Layer(square1.Top, square1SliceBack, 1), square1Middle / 2, top},
+	}
+
+	var faces [][]square1Face
+	for i, layer := range layers {
+		var sides, tops []square1Face
+
+		for _, f := range layer.Facets {
+			center := square1Centroid(f.Outline)
+
+			// Боковые грани, обращённые к камере
+			for _, e := range f.Edges {
+				dx, dz := e.To.X-e.From.X, e.To.Y-e.From.Y
+				length := math.Hypot(dx, dz)
+				normal := Point3{X: dz / length, Z: -dx / length}
+				if normal.X*(e.From.X-center.X)+normal.Z*(e.From.Y-center.Y) < 0 {
+					normal = normal.scale(-1)
+				}
+				if view.rotate(normal).Z <= 0 {
+					continue
+				}
+
+				// Грань в своих координатах: вдоль ребра и по высоте
+				at := func(p Point) Point3 {
+					return Point3{X: e.From.X + dx/length*p.X, Y: p.Y, Z: e.From.Y + dz/length*p.X}
+				}
+				local := []Point{{X: 0, Y: layer.Y0}, {X: length, Y: layer.Y0}, {X: length, Y: layer.Y1}, {X: 0, Y: layer.Y1}}
+				face := square1Face{Depth: -view.rotate(at(Point{X: length / 2, Y: (layer.Y0 + layer.Y1) / 2})).Z, Color: e.Color}
+				for _, p := range local {
+					face.Outline = append(face.Outline, project(at(p)))
+				}
+				for _, p := range insetPolygon(local, gap) {
+					face.Sticker = append(face.Sticker, project(at(p)))
+				}
+				sides = append(sides, face)
+			}
+
+			// Верхняя грань: у нижнего и среднего слоёв видна только основа
+			face := square1Face{Color: f.Color}
+			for _, p := range f.Outline {
+				face.Outline = append(face.Outline, project(Point3{X: p.X, Y: layer.Y1, Z: p.Y}))
+			}
+			if i == len(layers)-1 && f.Color != 0 {
+				for _, p := range insetPolygon(f.Outline, gap) {
+					face.Sticker = append(face.Sticker, project(Point3{X: p.X, Y: layer.Y1, Z: p.Y}))
+				}
+			}
+			tops = append(tops, face)
+		}
+
+		// Порядок художника: дальние боковые грани рисуются первыми, верх слоя — поверх них
+		sort.SliceStable(sides, func(i, j int) bool { return sides[i].Depth > sides[j].Depth })
+		faces = append(faces, append(sides, tops...))
+	}
+
+	// Считаем размер рамки (viewBox)
+	var outlines [][]Point
+	for _, layer := range faces {
+		for _, face := range layer {
+			outlines = append(outlines, face.Outline)
+		}
+	}
+	offset, viewBoxSize := fitOutlines(outlines, square1Border+2)
+
+	// // // // // СТРОИМ SVG
+
+	// Создаём рамку (viewBox)
+	GenerateViewBox(&builder, viewBoxSize.X, viewBoxSize.Y, square1.Rotate)
+	builder.WriteString(fmt.Sprintf("\r\n<g transform=\"translate(%.2f %.2f)\">", offset.X, offset.Y))
+
+	colorBase := colorMapRGBA[square1.Colors[Base]]
+	for i, name := range []string{"bottom", "middle", "top"} {
+		builder.WriteString(fmt.Sprintf("\r\n\t<g id=\"%s\">", name))
+		for _, face := range faces[i] {
+			builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"%s\" style=\"fill: %s; stroke: %s; stroke-width: %d; stroke-linejoin: round\"/>",
+				polygonPath(face.Outline), colorBase, colorBase, 2*square1Gap))
+			if face.Sticker != nil {
+				builder.WriteString(fmt.Sprintf("\r\n\t\t<path d=\"%s\" style=\"fill: %s\"/>",
+					roundedPolygonPath(face.Sticker, square1Round), colorMapRGBA[face.Color]))
+			}
+		}
+		builder.WriteString("\r\n\t</g>")
+	}
+
+	builder.WriteString("\r\n</g>")
+
+	// Закрываем рамку (viewBox)
+	CloseViewBox(&builder, square1.Rotate)
+
+	// Возвращаем сгенерированную SVG
+	return builder.String()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// square1Shape возвращает форму слоя: c — угол, e — ребро
+func square1Shape(pieces []Square1Piece) string {
+	var shape []byte
+	for _, piece := range pieces {
+		if piece.Corner {
+			shape = append(shape, 'c')
+		} else {
+			shape = append(shape, 'e')
+		}
+	}
+	return string(shape)
+}
+
+func TestParseSquare1Params(t *testing.T) {
+	tests := []struct {
+		state         string
+		top, bottom   string
+		flipped, gray bool
+	}{
+		{"A1B2C3D4-5E6F7G8H-s", "cececece", "ecececec", false, false},
+		{"a1b2c3d4-5e6f7g8h", "cececece", "ecececec", false, false},
+		{"A1BC2D34-5EF67G8H-f", "ceccecee", "ecceecec", true, false},
+		{"XXXXX00-0XX00XX0", "cccccee", "ecceecce", false, true},
+		{"xxxxx00-0xx00xx0-F", "cccccee", "ecceecce", true, true},
+		{"cubeshape", "cececece", "ecececec", false, true},
+		{"kite", "cceeecce", "cceeecce", false, true},
+		{"barrel-shield-f", "cceeccee", "ccceecee", true, true},
+		{"Mushroom-scallop", "cccceeee", "ceceecec", false, true},
+		{"star-X0X0X0X0", "cccccc", "cececece", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			square1, err := ParseSquare1Params(tt.state, "")
+			if err != nil {
+				t.Fatalf("ParseSquare1Params(%q): %v", tt.state, err)
+			}
+			if got := square1Shape(square1.Top); got != tt.top {
+				t.Errorf("top = %s, want %s", got, tt.top)
+			}
+			if got := square1Shape(square1.Bottom); got != tt.bottom {
+				t.Errorf("bottom = %s, want %s", got, tt.bottom)
+			}
+			if square1.Flipped != tt.flipped {
+				t.Errorf("flipped = %v, want %v", square1.Flipped, tt.flipped)
+			}
+			if gray := square1.Top[0].Color == 'X'; gray != tt.gray {
+				t.Errorf("gray = %v, want %v", gray, tt.gray)
+			}
+		})
+	}
+}
+
+func TestParseSquare1PieceColors(t *testing.T) {
+	square1, err := ParseSquare1Params("A1B2C3D4-5E6F7G8H", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Угол UBR: белый сверху, синий сзади и красный справа; ребро DF: жёлтый снизу и зелёный спереди
+	if got, want := square1.Top[0], (Square1Piece{Corner: true, Color: 'W', Sides: []rune{'B', 'R'}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("A = %+v, want %+v", got, want)
+	}
+	if got, want := square1.Bottom[0], (Square1Piece{Color: 'Y', Sides: []rune{'G'}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("5 = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSquare1ParamsRejectsBadInput(t *testing.T) {
+	for _, state := range []string{
+		"",
+		"A1B2C3D4",
+		"A1B2C3D4-5E6F7G8H-s-s",
+		"A1B2C3D4-5E6F7G8H-q",
+		"A1B2C3D-5E6F7G8H",
+		"A1B2C3D4X-5E6F7G8H",
+		"A1B2C3D4-5E6F7G8A",
+		"A1B2C3D4-5E6F7G89",
+		"cccccee-ecceecce",
+		"circle",
+		"kite-circle",
+	} {
+		if _, err := ParseSquare1Params(state, ""); err == nil {
+			t.Errorf("ParseSquare1Params(%q) returned no error", state)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StickerRef ссылка на элемент стороны: буква стороны и номер элемента
+// (слева направо, сверху вниз, начиная с 0)
+type StickerRef struct {
+	Face  rune
+	Index int
+}
+
+// StickerFrame положение элемента на картинке: центр и векторы шага сетки стороны.
+// Через векторы U и V фигуры, нарисованные для квадратного элемента, переносятся на искажённую сторону
+type StickerFrame struct {
+	Center     Point // Центр элемента
+	U          Point // Шаг на один элемент вправо
+	V          Point // Шаг на один элемент вниз
+	Row, Col   int   // Строка и столбец элемента на стороне
+	Rows, Cols int   // Размер стороны в элементах
+}
+
+// Transform возвращает атрибут transform, переводящий единичный элемент
+// (координаты от -0.5 до 0.5) в положение элемента на картинке
+func (f StickerFrame) Transform() string {
+	return fmt.Sprintf("matrix(%.2f %.2f %.2f %.2f %.2f %.2f)", f.U.X, f.U.Y, f.V.X, f.V.Y, f.Center.X, f.Center.Y)
+}
+
+// stickerFrame находит элемент плоского вида. Элементы верхней стороны
+// (центральная часть картинки) обозначаются буквой U
+func (cube *FlatCube) stickerFrame(ref StickerRef) (StickerFrame, bool) {
+	X, Y := cube.Size.X, cube.Size.Y
+	if ref.Face != 'U' || ref.Index >= X*Y {
+		return StickerFrame{}, false
+	}
+	x, y := ref.Index%X, ref.Index/X
+	return StickerFrame{
+		Center: Point{X: 10 + float64(x)*49 + 21.5, Y: 10 + float64(y)*49 + 21.5},
+		U:      Point{X: 49},
+		V:      Point{Y: 49},
+		Row:    y, Col: x, Rows: Y, Cols: X,
+	}, true
+}
+
+// unfoldedStickerFrame находит элемент развёртки. Доступны элементы всех шести сторон
+func (cube *FlatCube) unfoldedStickerFrame(ref StickerRef) (StickerFrame, bool) {
+	var side Side
+	found := false
+	for _, s := range stateSides {
+		if []rune(strings.ToUpper(s.String()))[0] == ref.Face {
+			side, found = s, true
+		}
+	}
+	w, h := cube.Size.faceDims(side)
+	if !found || ref.Index >= w*h {
+		return StickerFrame{}, false
+	}
+	base := unfoldedSideParams(cube.Size)[side].Base
+	x, y := ref.Index%w, ref.Index/w
+	return StickerFrame{
+		Center: Point{X: base.X + 7 + float64(x)*49 + 21.5, Y: base.Y + 7 + float64(y)*49 + 21.5},
+		U:      Point{X: 49},
+		V:      Point{Y: 49},
+		Row:    y, Col: x, Rows: h, Cols: w,
+	}, true
+}
+
+// stickerFrame находит элемент изометрического вида. Элементы видимых сторон
+// обозначаются буквами F, U и R, сторона U нумеруется как в развёртке (начиная с заднего ряда)
+func (cube *IsometricCube) stickerFrame(ref StickerRef) (StickerFrame, bool) {
+	if cube.Layers.custom() {
+		return cube.layeredStickerFrame(ref)
+	}
+	sideParams := isometricSideParams(cube.Size)
+	X, Y, Z := cube.Size.X, cube.Size.Y, cube.Size.Z
+
+	switch ref.Face {
+	case 'F', 'R':
+		side, cols := Front, X
+		if ref.Face == 'R' {
+			side, cols = Right, Z
+		}
+		if ref.Index >= cols*Y {
+			break
+		}
+		p := sideParams[side]
+		r, c := ref.Index/cols, ref.Index%cols
+		return StickerFrame{
+			Center: p.stickerCenter(r, c),
+			U:      Point{X: p.Offset.X, Y: p.Multi.Y},
+			V:      Point{X: p.Multi.X, Y: p.Offset.Y},
+			Row:    r, Col: c, Rows: Y, Cols: cols,
+		}, true
+	case 'U':
+		if ref.Index >= X*Z {
+			break
+		}
+		// Верхняя сторона в изометрии хранится по столбцам (см. CubeState.Isometric)
+		p := sideParams[Up]
+		r, c := ref.Index/X, ref.Index%X
+		return StickerFrame{
+			Center: p.stickerCenter(c, Z-1-r),
+			U:      Point{X: p.Multi.X, Y: p.Offset.Y},
+			V:      Point{X: -p.Offset.X, Y: -p.Multi.Y},
+			Row:    r, Col: c, Rows: Z, Cols: X,
+		}, true
+	}
+	return StickerFrame{}, false
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SupercubeSpec описание метки направления наклейки из параметра запроса
+type SupercubeSpec struct {
+	Sticker StickerRef // Элемент, на котором рисуется метка
+	Turns   int        // Поворот метки в четвертях оборота по часовой стрелке (0 — метка у верхнего края)
+	Color   rune       // Цвет метки
+	Auto    string     // Тип автоматических меток (centers, all)
+}
+
+// SupercubeMarker метка направления наклейки, готовая к построению
+type SupercubeMarker struct {
+	Frame StickerFrame // Положение элемента
+	Turns int          // Поворот метки в четвертях оборота по часовой стрелке
+	Color rune         // Цвет метки
+}
+
+// Углы поворота метки в градусах
+var supercubeAngles = map[string]int{"0": 0, "90": 1, "180": 2, "270": 3}
+
+// ParseSupercube парсит список меток направления вида U4-90,F4-180-R,auto.
+// Угол (0, 90, 180 или 270) задаёт, к какому краю элемента повёрнута метка: 0 — к верхнему,
+// 90 — к правому и т.д. auto вычисляет метки по состоянию кубика, допускает опции centers/all и цвет
+func ParseSupercube(pSupercube string) ([]SupercubeSpec, error) {
+	var specs []SupercubeSpec
+	if pSupercube == "" {
+		return specs, nil
+	}
+
+	for _, pMarker := range strings.Split(pSupercube, ",") {
+		parts := strings.Split(pMarker, "-")
+		spec := SupercubeSpec{Turns: -1, Color: 'K'}
+
+		if parts[0] == "auto" {
+			spec.Auto = "centers"
+		} else if m := twistStickerRegexp.FindStringSubmatch(parts[0]); m != nil {
+			index, err := strconv.Atoi(m[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid supercube marker sticker %q: %w", parts[0], err)
+			}
+			spec.Sticker = StickerRef{Face: rune(m[1][0]), Index: index}
+		} else {
+			return nil, fmt.Errorf("invalid supercube marker %q: expected a sticker like U4", pMarker)
+		}
+
+		for _, option := range parts[1:] {
+			turns, isAngle := supercubeAngles[option]
+			switch {
+			case spec.Auto == "" && isAngle:
+				spec.Turns = turns
+			case spec.Auto != "" && (option == "centers" || option == "all"):
+				spec.Auto = option
+			case len(option) == 1 && colorMapRGBA[rune(option[0])] != "":
+				spec.Color = rune(option[0])
+			default:
+				return nil, fmt.Errorf("invalid supercube marker option %q", option)
+			}
+		}
+		if spec.Auto == "" && spec.Turns < 0 {
+			return nil, fmt.Errorf("supercube marker %q has no angle (0, 90, 180 or 270)", pMarker)
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// ResolveSupercube разворачивает автоматические метки по состоянию state
+// и переводит ссылки на элементы в положения с помощью функции locate.
+// Автоматические метки рисуются только на элементах, которые есть на картинке
+func ResolveSupercube(pSupercube string, state *CubeState, locate func(ref StickerRef) (StickerFrame, bool)) ([]SupercubeMarker, error) {
+	parsed, err := ParseSupercube(pSupercube)
+	if err != nil {
+		return nil, err
+	}
+
+	var markers []SupercubeMarker
+	for _, spec := range parsed {
+		if spec.Auto == "" {
+			frame, ok := locate(spec.Sticker)
+			if !ok {
+				return nil, fmt.Errorf("unknown sticker %c%d for supercube marker", spec.Sticker.Face, spec.Sticker.Index)
+			}
+			markers = append(markers, SupercubeMarker{Frame: frame, Turns: spec.Turns, Color: spec.Color})
+			continue
+		}
+		if state == nil {
+			return nil, fmt.Errorf("automatic supercube markers require a cube state (alg or case)")
+		}
+		for _, auto := range state.StickerTurns(spec.Auto, spec.Color) {
+			if frame, ok := locate(auto.Sticker); ok {
+				markers = append(markers, SupercubeMarker{Frame: frame, Turns: auto.Turns, Color: auto.Color})
+			}
+		}
+	}
+	return markers, nil
+}
+
+// StickerTurns вычисляет, насколько повёрнута каждая наклейка относительно своей клетки развёртки:
+// в собранном кубике метки всех наклеек смотрят к верхнему краю клетки.
+// kind — centers (только центры, у которых направление не видно по соседним наклейкам) или all
+func (s *CubeState) StickerTurns(kind string, color rune) []SupercubeSpec {
+	var specs []SupercubeSpec
+	for _, st := range s.Stickers {
+		if kind == "centers" && !s.isCenter(st.Pos) {
+			continue
+		}
+
+		side, r, c := s.stickerIndex(st.Pos, st.Normal)
+		w, _ := s.Size.faceDims(side)
+		spec := SupercubeSpec{
+			Sticker: StickerRef{Face: []rune(strings.ToUpper(side.String()))[0], Index: r*w + c},
+			Color:   color,
+		}
+
+		// Правый край клетки — поворот верхнего по часовой стрелке, если смотреть на сторону снаружи
+		up := sideUps[side]
+		right := up.cross(sideNormals[side])
+		switch st.Up {
+		case up:
+			spec.Turns = 0
+		case right:
+			spec.Turns = 1
+		case Vec3{X: -up.X, Y: -up.Y, Z: -up.Z}:
+			spec.Turns = 2
+		default:
+			spec.Turns = 3
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// GenerateSupercubeMarkers генерирует слой меток направления поверх элементов.
+// Метка — полоска у края элемента, она рисуется в координатах единичного элемента
+// и переносится на сторону через transform, поэтому на изометрии наклоняется вместе со стороной
+func GenerateSupercubeMarkers(builder *strings.Builder, markers []SupercubeMarker) {
+	if len(markers) == 0 {
+		return
+	}
+
+	// Начало группы
+	builder.WriteString("\r\n\t<g id=\"supercube\">")
+	for i, marker := range markers {
+		// Полоска у верхнего края, повёрнутая на четверти оборота по часовой стрелке
+		a, b := Point{X: -0.2, Y: -0.32}, Point{X: 0.2, Y: -0.32}
+		for t := 0; t < marker.Turns; t++ {
+			a, b = Point{X: -a.Y, Y: a.X}, Point{X: -b.Y, Y: b.X}
+		}
+
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<path id=\"supercube-%d\" transform=\"%s\" d=\"M%.3f %.3fL%.3f %.3f\" style=\"fill: none; stroke: %s; stroke-width: 0.08; stroke-linecap: round\"/>",
+			i+1, marker.Frame.Transform(), a.X, a.Y, b.X, b.Y, colorMapRGBA[marker.Color]))
+	}
+	// Закрытие группы
+	builder.WriteString("\r\n\t</g>")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSupercube(t *testing.T) {
+	tests := []struct {
+		supercube string
+		want      []SupercubeSpec
+	}{
+		{"", []SupercubeSpec(nil)},
+		{"U4-90,F4-180-R,R0-0", []SupercubeSpec{
+			{Sticker: StickerRef{'U', 4}, Turns: 1, Color: 'K'},
+			{Sticker: StickerRef{'F', 4}, Turns: 2, Color: 'R'},
+			{Sticker: StickerRef{'R', 0}, Turns: 0, Color: 'K'},
+		}},
+		{"auto", []SupercubeSpec{{Turns: -1, Color: 'K', Auto: "centers"}}},
+		{"auto-all-W", []SupercubeSpec{{Turns: -1, Color: 'W', Auto: "all"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.supercube, func(t *testing.T) {
+			got, err := ParseSupercube(tt.supercube)
+			if err != nil {
+				t.Fatalf("ParseSupercube(%q): %v", tt.supercube, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSupercube(%q) = %+v, want %+v", tt.supercube, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSupercubeRejectsBadInput(t *testing.T) {
+	for _, supercube := range []string{
+		"U4",
+		"U4-45",
+		"U4-90-Q",
+		"Q4-90",
+		"U99999999999999999999-90",
+		"auto-90",
+		"U4-all",
+	} {
+		if _, err := ParseSupercube(supercube); err == nil {
+			t.Errorf("ParseSupercube(%q) returned no error", supercube)
+		}
+	}
+}
+
+func TestStickerTurns(t *testing.T) {
+	tests := []struct {
+		alg   string
+		turns map[StickerRef]int // Ненулевые повороты центров
+	}{
+		{"", map[StickerRef]int{}},
+		{"R", map[StickerRef]int{{'R', 4}: 1}},
+		{"R2", map[StickerRef]int{{'R', 4}: 2}},
+		{"R'", map[StickerRef]int{{'R', 4}: 3}},
+		{"U F'", map[StickerRef]int{{'U', 4}: 1, {'F', 4}: 3}},
+		// Центры поворачиваются и при вращении всего кубика
+		{"y", map[StickerRef]int{{'U', 4}: 1, {'D', 4}: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			state := solvedState(t, "3x3x3")
+			if err := state.ApplyAlgorithm(tt.alg); err != nil {
+				t.Fatal(err)
+			}
+
+			specs := state.StickerTurns("centers", 'K')
+			if len(specs) != 6 {
+				t.Fatalf("got %d centers, want 6", len(specs))
+			}
+			for _, spec := range specs {
+				if spec.Turns != tt.turns[spec.Sticker] {
+					t.Errorf("%c%d turns = %d, want %d", spec.Sticker.Face, spec.Sticker.Index, spec.Turns, tt.turns[spec.Sticker])
+				}
+			}
+		})
+	}
+}
+
+func TestStickerTurnsAll(t *testing.T) {
+	// В собранном кубике все метки смотрят к верхнему краю клетки, а после ходов
+	// часть наклеек оказывается повёрнутой
+	state := solvedState(t, "3x3x3")
+	if specs := state.StickerTurns("all", 'K'); len(specs) != 54 {
+		t.Fatalf("got %d stickers, want 54", len(specs))
+	}
+	for _, spec := range state.StickerTurns("all", 'K') {
+		if spec.Turns != 0 {
+			t.Errorf("solved %c%d turns = %d, want 0", spec.Sticker.Face, spec.Sticker.Index, spec.Turns)
+		}
+	}
+
+	if err := state.ApplyAlgorithm("R U R' U'"); err != nil {
+		t.Fatal(err)
+	}
+	turned := 0
+	for _, spec := range state.StickerTurns("all", 'K') {
+		if spec.Turns != 0 {
+			turned++
+		}
+	}
+	if turned == 0 {
+		t.Error("no stickers are turned after R U R' U'")
+	}
+}
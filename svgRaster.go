@@ -0,0 +1,625 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svgNode элемент SVG-документа
+type svgNode struct {
+	Name     string
+	Attrs    map[string]string
+	Children []*svgNode
+}
+
+// svgStyle наследуемые свойства оформления
+type svgStyle struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth float64
+	LineCap     string
+	Opacity     float64
+}
+
+// svgRenderer растеризует SVG, сгенерированные этим сервисом
+type svgRenderer struct {
+	img     *image.RGBA
+	raster  *Rasterizer
+	markers map[string]*svgNode
+}
+
+// RasterizeSVG растеризует SVG так, чтобы большая сторона картинки была равна size пикселей
+func RasterizeSVG(svg string, size int) (*image.RGBA, error) {
+	root, err := parseSVGTree(svg)
+	if err != nil {
+		return nil, err
+	}
+	if root.Name != "svg" {
+		return nil, fmt.Errorf("invalid svg: root element is %q", root.Name)
+	}
+
+	viewBox := parseNumbers(root.Attrs["viewBox"])
+	if len(viewBox) != 4 || viewBox[2] <= 0 || viewBox[3] <= 0 {
+		return nil, fmt.Errorf("invalid svg: missing viewBox")
+	}
+
+	// Размер картинки в пикселях
+	scale := float64(size) / math.Max(viewBox[2], viewBox[3])
+	width := int(math.Max(1, math.Round(viewBox[2]*scale)))
+	height := int(math.Max(1, math.Round(viewBox[3]*scale)))
+
+	r := &svgRenderer{
+		img:     image.NewRGBA(image.Rect(0, 0, width, height)),
+		raster:  NewRasterizer(width, height),
+		markers: make(map[string]*svgNode),
+	}
+	r.collectMarkers(root)
+
+	transform := Matrix{scale, 0, 0, scale, -viewBox[0] * scale, -viewBox[1] * scale}
+	style := svgStyle{Fill: "#000000", Stroke: "none", StrokeWidth: 1, LineCap: "butt", Opacity: 1}
+	for _, child := range root.Children {
+		r.render(child, transform, style)
+	}
+
+	return r.img, nil
+}
+
+// parseSVGTree разбирает XML в дерево элементов
+func parseSVGTree(svg string) (*svgNode, error) {
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	var stack []*svgNode
+	var root *svgNode
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &svgNode{Name: t.Name.Local, Attrs: make(map[string]string)}
+			for _, attr := range t.Attr {
+				node.Attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else if root == nil {
+				root = node
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("invalid svg: no elements")
+	}
+	return root, nil
+}
+
+// collectMarkers собирает маркеры (наконечники стрелок) по id
+func (r *svgRenderer) collectMarkers(node *svgNode) {
+	if node.Name == "marker" {
+		r.markers[node.Attrs["id"]] = node
+		return
+	}
+	for _, child := range node.Children {
+		r.collectMarkers(child)
+	}
+}
+
+// render рисует элемент и его потомков
+func (r *svgRenderer) render(node *svgNode, transform Matrix, style svgStyle) {
+	if node.Name == "defs" || node.Name == "marker" {
+		return
+	}
+
+	transform = transform.Multiply(parseTransform(node.Attrs["transform"]))
+	style = style.apply(node)
+
+	switch node.Name {
+	case "g":
+		for _, child := range node.Children {
+			r.render(child, transform, style)
+		}
+	case "svg":
+		// Вложенная картинка: переносим её viewBox в прямоугольник x, y, width, height
+		x, _ := strconv.ParseFloat(node.Attrs["x"], 64)
+		y, _ := strconv.ParseFloat(node.Attrs["y"], 64)
+		inner := Matrix{1, 0, 0, 1, x, y}
+		viewBox := parseNumbers(node.Attrs["viewBox"])
+		w, errW := strconv.ParseFloat(node.Attrs["width"], 64)
+		h, errH := strconv.ParseFloat(node.Attrs["height"], 64)
+		if len(viewBox) == 4 && errW == nil && errH == nil && viewBox[2] > 0 && viewBox[3] > 0 {
+			s := math.Min(w/viewBox[2], h/viewBox[3])
+			inner = Matrix{s, 0, 0, s, x - viewBox[0]*s, y - viewBox[1]*s}
+		}
+		for _, child := range node.Children {
+			r.render(child, transform.Multiply(inner), style)
+		}
+	case "path", "rect", "circle", "ellipse", "polygon", "polyline", "line":
+		subpaths := shapeSubpaths(node, transform.Scale())
+		r.drawShape(subpaths, transform, style, node.Name != "polyline" && node.Name != "line")
+		r.drawMarkers(node, subpaths, transform, style)
+	}
+}
+
+// apply применяет к наследуемому стилю атрибуты элемента
+func (s svgStyle) apply(node *svgNode) svgStyle {
+	props := make(map[string]string)
+	for _, name := range []string{"fill", "stroke", "stroke-width", "stroke-linecap", "opacity"} {
+		if v, ok := node.Attrs[name]; ok {
+			props[name] = v
+		}
+	}
+	for _, decl := range strings.Split(node.Attrs["style"], ";") {
+		name, value, ok := strings.Cut(decl, ":")
+		if ok {
+			props[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
+	if v, ok := props["fill"]; ok {
+		s.Fill = v
+	}
+	if v, ok := props["stroke"]; ok {
+		s.Stroke = v
+	}
+	if v, ok := props["stroke-width"]; ok {
+		if w, err := strconv.ParseFloat(strings.TrimSuffix(v, "px"), 64); err == nil {
+			s.StrokeWidth = w
+		}
+	}
+	if v, ok := props["stroke-linecap"]; ok {
+		s.LineCap = v
+	}
+	if v, ok := props["opacity"]; ok {
+		if o, err := strconv.ParseFloat(v, 64); err == nil {
+			s.Opacity *= o
+		}
+	}
+	return s
+}
+
+// drawShape закрашивает и обводит фигуру
+func (r *svgRenderer) drawShape(subpaths []svgSubpath, transform Matrix, style svgStyle, fillable bool) {
+	if fill, ok := parseColor(style.Fill); ok && fillable {
+		for _, sp := range subpaths {
+			if len(sp.Points) > 2 {
+				r.raster.AddPolygon(transformPoints(sp.Points, transform))
+			}
+		}
+		r.raster.Composite(r.img, fill, style.Opacity)
+	}
+
+	if stroke, ok := parseColor(style.Stroke); ok {
+		for _, sp := range subpaths {
+			polygons := strokePolygons(sp.Points, sp.Closed, style.StrokeWidth, style.LineCap == "round", transform.Scale())
+			for _, polygon := range polygons {
+				r.raster.AddPolygon(transformPoints(polygon, transform))
+			}
+		}
+		r.raster.Composite(r.img, stroke, style.Opacity)
+	}
+}
+
+// drawMarkers рисует маркеры marker-start и marker-end на концах фигуры
+func (r *svgRenderer) drawMarkers(node *svgNode, subpaths []svgSubpath, transform Matrix, style svgStyle) {
+	if len(subpaths) == 0 {
+		return
+	}
+	first := subpaths[0].Points
+	last := subpaths[len(subpaths)-1].Points
+	if len(first) < 2 || len(last) < 2 {
+		return
+	}
+
+	for _, attr := range []string{"marker-start", "marker-end"} {
+		ref := strings.TrimSuffix(strings.TrimPrefix(node.Attrs[attr], "url(#"), ")")
+		marker, ok := r.markers[ref]
+		if !ok {
+			continue
+		}
+
+		// Положение и направление маркера
+		var at Point
+		var angle float64
+		if attr == "marker-start" {
+			at = first[0]
+			angle = math.Atan2(first[1].Y-first[0].Y, first[1].X-first[0].X)
+			if marker.Attrs["orient"] == "auto-start-reverse" {
+				angle += math.Pi
+			}
+		} else {
+			at = last[len(last)-1]
+			prev := last[len(last)-2]
+			angle = math.Atan2(at.Y-prev.Y, at.X-prev.X)
+		}
+		if o, err := strconv.ParseFloat(marker.Attrs["orient"], 64); err == nil {
+			angle = o * math.Pi / 180
+		}
+
+		// Размер маркера задаётся в толщинах линии
+		markerWidth := parseFloatDefault(marker.Attrs["markerWidth"], 3)
+		markerHeight := parseFloatDefault(marker.Attrs["markerHeight"], 3)
+		unit := style.StrokeWidth
+		if marker.Attrs["markerUnits"] == "userSpaceOnUse" {
+			unit = 1
+		}
+		sx, sy := markerWidth*unit, markerHeight*unit
+		viewBox := parseNumbers(marker.Attrs["viewBox"])
+		if len(viewBox) == 4 && viewBox[2] > 0 && viewBox[3] > 0 {
+			sx, sy = sx/viewBox[2], sy/viewBox[3]
+			sx = math.Min(sx, sy)
+			sy = sx
+		}
+		refX := parseFloatDefault(marker.Attrs["refX"], 0)
+		refY := parseFloatDefault(marker.Attrs["refY"], 0)
+
+		cos, sin := math.Cos(angle), math.Sin(angle)
+		markerTransform := transform.
+			Multiply(Matrix{cos, sin, -sin, cos, at.X, at.Y}).
+			Multiply(Matrix{sx, 0, 0, sy, -refX * sx, -refY * sy})
+
+		markerStyle := svgStyle{Fill: "#000000", Stroke: "none", StrokeWidth: 1, LineCap: "butt", Opacity: style.Opacity}
+		for _, child := range marker.Children {
+			r.render(child, markerTransform, markerStyle)
+		}
+	}
+}
+
+// svgSubpath ломаная, полученная из контура SVG
+type svgSubpath struct {
+	Points []Point
+	Closed bool
+}
+
+// shapeSubpaths переводит фигуру SVG в ломаные (в координатах элемента)
+func shapeSubpaths(node *svgNode, scale float64) []svgSubpath {
+	attr := func(name string) float64 {
+		return parseFloatDefault(node.Attrs[name], 0)
+	}
+
+	switch node.Name {
+	case "path":
+		return parsePathData(node.Attrs["d"], scale)
+	case "rect":
+		x, y, w, h := attr("x"), attr("y"), attr("width"), attr("height")
+		rx, ry := attr("rx"), attr("ry")
+		if _, ok := node.Attrs["ry"]; !ok {
+			ry = rx
+		}
+		if _, ok := node.Attrs["rx"]; !ok {
+			rx = ry
+		}
+		rx, ry = math.Min(rx, w/2), math.Min(ry, h/2)
+		if rx <= 0 || ry <= 0 {
+			return []svgSubpath{{Points: []Point{{X: x, Y: y}, {X: x + w, Y: y}, {X: x + w, Y: y + h}, {X: x, Y: y + h}}, Closed: true}}
+		}
+		d := fmt.Sprintf("M%f %fH%fA%f %f 0 0 1 %f %fV%fA%f %f 0 0 1 %f %fH%fA%f %f 0 0 1 %f %fV%fA%f %f 0 0 1 %f %fz",
+			x+rx, y, x+w-rx, rx, ry, x+w, y+ry, y+h-ry, rx, ry, x+w-rx, y+h, x+rx, rx, ry, x, y+h-ry, y+ry, rx, ry, x+rx, y)
+		return parsePathData(d, scale)
+	case "circle", "ellipse":
+		cx, cy := attr("cx"), attr("cy")
+		rx, ry := attr("r"), attr("r")
+		if node.Name == "ellipse" {
+			rx, ry = attr("rx"), attr("ry")
+		}
+		d := fmt.Sprintf("M%f %fA%f %f 0 1 1 %f %fA%f %f 0 1 1 %f %fz", cx-rx, cy, rx, ry, cx+rx, cy, rx, ry, cx-rx, cy)
+		return parsePathData(d, scale)
+	case "polygon", "polyline":
+		numbers := parseNumbers(node.Attrs["points"])
+		var points []Point
+		for i := 0; i+1 < len(numbers); i += 2 {
+			points = append(points, Point{X: numbers[i], Y: numbers[i+1]})
+		}
+		return []svgSubpath{{Points: points, Closed: node.Name == "polygon"}}
+	case "line":
+		return []svgSubpath{{Points: []Point{{X: attr("x1"), Y: attr("y1")}, {X: attr("x2"), Y: attr("y2")}}}}
+	}
+	return nil
+}
+
+// parsePathData переводит атрибут d в ломаные
+func parsePathData(d string, scale float64) []svgSubpath {
+	var subpaths []svgSubpath
+	var current []Point
+	var pos, start, lastControl Point
+	var lastCommand byte
+
+	flush := func(closed bool) {
+		if len(current) > 1 {
+			subpaths = append(subpaths, svgSubpath{Points: current, Closed: closed})
+		}
+		current = nil
+	}
+	lineTo := func(points ...Point) {
+		if len(current) == 0 {
+			current = append(current, pos)
+		}
+		current = append(current, points...)
+	}
+
+	s := &pathScanner{data: d}
+	var command byte
+	for {
+		if c, ok := s.command(); ok {
+			command = c
+		} else if command == 0 || command|0x20 == 'z' || !s.hasNumber() {
+			break
+		}
+
+		relative := command >= 'a'
+		offset := Point{}
+		if relative {
+			offset = pos
+		}
+		point := func() Point {
+			x, y := s.number(), s.number()
+			return Point{X: x + offset.X, Y: y + offset.Y}
+		}
+
+		switch command | 0x20 {
+		case 'm':
+			flush(false)
+			pos = point()
+			start = pos
+			current = []Point{pos}
+			// Последующие пары координат после M — это L
+			if relative {
+				command = 'l'
+			} else {
+				command = 'L'
+			}
+		case 'l':
+			p := point()
+			lineTo(p)
+			pos = p
+		case 'h':
+			x := s.number() + offset.X
+			pos = Point{X: x, Y: pos.Y}
+			lineTo(pos)
+		case 'v':
+			y := s.number() + offset.Y
+			pos = Point{X: pos.X, Y: y}
+			lineTo(pos)
+		case 'c', 's':
+			var c1 Point
+			if command|0x20 == 'c' {
+				c1 = point()
+			} else if lastCommand|0x20 == 'c' || lastCommand|0x20 == 's' {
+				c1 = Point{X: 2*pos.X - lastControl.X, Y: 2*pos.Y - lastControl.Y}
+			} else {
+				c1 = pos
+			}
+			c2 := point()
+			p := point()
+			segments := curveSegments((math.Hypot(c1.X-pos.X, c1.Y-pos.Y) + math.Hypot(c2.X-c1.X, c2.Y-c1.Y) + math.Hypot(p.X-c2.X, p.Y-c2.Y)) * scale)
+			lineTo(flattenCubic(pos, c1, c2, p, segments)...)
+			lastControl = c2
+			pos = p
+		case 'q', 't':
+			var c1 Point
+			if command|0x20 == 'q' {
+				c1 = point()
+			} else if lastCommand|0x20 == 'q' || lastCommand|0x20 == 't' {
+				c1 = Point{X: 2*pos.X - lastControl.X, Y: 2*pos.Y - lastControl.Y}
+			} else {
+				c1 = pos
+			}
+			p := point()
+			segments := curveSegments((math.Hypot(c1.X-pos.X, c1.Y-pos.Y) + math.Hypot(p.X-c1.X, p.Y-c1.Y)) * scale)
+			lineTo(flattenQuadratic(pos, c1, p, segments)...)
+			lastControl = c1
+			pos = p
+		case 'a':
+			rx, ry, rotation := s.number(), s.number(), s.number()
+			largeArc, sweep := s.flag(), s.flag()
+			p := point()
+			lineTo(flattenArc(pos, rx, ry, rotation, largeArc, sweep, p, scale)...)
+			pos = p
+		case 'z':
+			if len(current) > 0 {
+				flush(true)
+			}
+			pos = start
+		default:
+			return subpaths
+		}
+		lastCommand = command
+		if s.err {
+			break
+		}
+	}
+	flush(false)
+
+	return subpaths
+}
+
+// pathScanner читает команды и числа из атрибута d
+type pathScanner struct {
+	data string
+	pos  int
+	err  bool
+}
+
+func (s *pathScanner) skip() {
+	for s.pos < len(s.data) && strings.ContainsRune(" \t\r\n,", rune(s.data[s.pos])) {
+		s.pos++
+	}
+}
+
+// command читает букву команды, если она следующая
+func (s *pathScanner) command() (byte, bool) {
+	s.skip()
+	if s.pos < len(s.data) && strings.ContainsRune("MmLlHhVvCcSsQqTtAaZz", rune(s.data[s.pos])) {
+		s.pos++
+		return s.data[s.pos-1], true
+	}
+	return 0, false
+}
+
+// hasNumber проверяет, следует ли дальше число
+func (s *pathScanner) hasNumber() bool {
+	s.skip()
+	return s.pos < len(s.data) && strings.ContainsRune("+-.0123456789", rune(s.data[s.pos]))
+}
+
+// number читает число; числа могут идти без разделителей, например 2.75-1.59 или .5.5
+func (s *pathScanner) number() float64 {
+	s.skip()
+	start := s.pos
+	if s.pos < len(s.data) && (s.data[s.pos] == '-' || s.data[s.pos] == '+') {
+		s.pos++
+	}
+	dot, exp := false, false
+	for s.pos < len(s.data) {
+		c := s.data[s.pos]
+		switch {
+		case c >= '0' && c <= '9':
+		case c == '.' && !dot && !exp:
+			dot = true
+		case (c == 'e' || c == 'E') && !exp:
+			exp = true
+			if s.pos+1 < len(s.data) && (s.data[s.pos+1] == '-' || s.data[s.pos+1] == '+') {
+				s.pos++
+			}
+		default:
+			goto done
+		}
+		s.pos++
+	}
+done:
+	value, err := strconv.ParseFloat(s.data[start:s.pos], 64)
+	if err != nil {
+		s.err = true
+	}
+	return value
+}
+
+// flag читает флаг дуги: одну цифру 0 или 1, которая может идти без разделителя
+func (s *pathScanner) flag() bool {
+	s.skip()
+	if s.pos < len(s.data) && (s.data[s.pos] == '0' || s.data[s.pos] == '1') {
+		s.pos++
+		return s.data[s.pos-1] == '1'
+	}
+	s.err = true
+	return false
+}
+
+// parseTransform разбирает атрибут transform
+func parseTransform(value string) Matrix {
+	m := IdentityMatrix()
+	for {
+		open := strings.Index(value, "(")
+		closing := strings.Index(value, ")")
+		if open < 0 || closing < open {
+			return m
+		}
+		name := strings.TrimSpace(strings.Trim(value[:open], " ,"))
+		args := parseNumbers(value[open+1 : closing])
+		value = value[closing+1:]
+
+		arg := func(i int, def float64) float64 {
+			if i < len(args) {
+				return args[i]
+			}
+			return def
+		}
+
+		var t Matrix
+		switch name {
+		case "matrix":
+			if len(args) != 6 {
+				continue
+			}
+			t = Matrix{args[0], args[1], args[2], args[3], args[4], args[5]}
+		case "translate":
+			t = Matrix{1, 0, 0, 1, arg(0, 0), arg(1, 0)}
+		case "scale":
+			t = Matrix{arg(0, 1), 0, 0, arg(1, arg(0, 1)), 0, 0}
+		case "rotate":
+			a := arg(0, 0) * math.Pi / 180
+			cx, cy := arg(1, 0), arg(2, 0)
+			cos, sin := math.Cos(a), math.Sin(a)
+			t = Matrix{1, 0, 0, 1, cx, cy}.Multiply(Matrix{cos, sin, -sin, cos, 0, 0}).Multiply(Matrix{1, 0, 0, 1, -cx, -cy})
+		default:
+			continue
+		}
+		m = m.Multiply(t)
+	}
+}
+
+// parseNumbers разбирает список чисел, разделённых пробелами или запятыми
+func parseNumbers(value string) []float64 {
+	var numbers []float64
+	s := &pathScanner{data: value}
+	for s.hasNumber() {
+		n := s.number()
+		if s.err {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// parseFloatDefault разбирает число или возвращает значение по умолчанию
+func parseFloatDefault(value string, def float64) float64 {
+	if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+		return f
+	}
+	return def
+}
+
+// parseColor разбирает цвет (#rgb, #rrggbb, #rrggbbaa и несколько имён)
+func parseColor(value string) (color.NRGBA, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "", "none", "transparent":
+		return color.NRGBA{}, false
+	case "black":
+		return color.NRGBA{A: 255}, true
+	case "white":
+		return color.NRGBA{R: 255, G: 255, B: 255, A: 255}, true
+	}
+	if !strings.HasPrefix(value, "#") {
+		return color.NRGBA{}, false
+	}
+
+	hex := value[1:]
+	if len(hex) == 3 || len(hex) == 4 {
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 {
+		return color.NRGBA{}, false
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, true
+}
+
+// transformPoints применяет преобразование к точкам
+func transformPoints(points []Point, m Matrix) []Point {
+	result := make([]Point, len(points))
+	for i, p := range points {
+		result[i] = m.Apply(p)
+	}
+	return result
+}
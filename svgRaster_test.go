@@ -0,0 +1,105 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestRasterizeSVGSize(t *testing.T) {
+	tests := []struct {
+		viewBox       string
+		size          int
+		width, height int
+	}{
+		{"0 0 200 100", 100, 100, 50},
+		{"0 0 100 200", 100, 50, 100},
+		{"-10 -10 50 50", 64, 64, 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.viewBox, func(t *testing.T) {
+			img, err := RasterizeSVG(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="`+tt.viewBox+`"></svg>`, tt.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.width || b.Dy() != tt.height {
+				t.Errorf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestRasterizeSVGPixels(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	clear := color.RGBA{}
+
+	type pixel struct {
+		x, y int
+		want color.RGBA
+	}
+	tests := []struct {
+		name   string
+		svg    string
+		pixels []pixel
+	}{
+		{
+			"rect",
+			`<svg viewBox="0 0 100 100"><rect x="20" y="20" width="60" height="60" style="fill: #ff0000"/></svg>`,
+			[]pixel{{50, 50, red}, {10, 10, clear}, {90, 50, clear}},
+		},
+		{
+			// Контур дыры обходится в обратную сторону и вырезается
+			"hole",
+			`<svg viewBox="0 0 100 100"><path d="M10 10H90V90H10zM30 30V70H70V30z" style="fill: #0000ff"/></svg>`,
+			[]pixel{{20, 50, blue}, {50, 50, clear}, {80, 80, blue}},
+		},
+		{
+			"group transform",
+			`<svg viewBox="0 0 100 100"><g transform="translate(50 0)"><rect width="50" height="50" fill="#ff0000"/></g></svg>`,
+			[]pixel{{75, 25, red}, {25, 25, clear}, {75, 75, clear}},
+		},
+		{
+			"nested svg",
+			`<svg viewBox="0 0 100 100"><svg x="50" y="50" width="50" height="50" viewBox="0 0 10 10"><rect width="10" height="10" fill="#0000ff"/></svg></svg>`,
+			[]pixel{{75, 75, blue}, {25, 25, clear}},
+		},
+		{
+			"stroke",
+			`<svg viewBox="0 0 100 100"><path d="M10 50H90" style="fill: none; stroke: #ff0000; stroke-width: 10"/></svg>`,
+			[]pixel{{50, 50, red}, {50, 30, clear}},
+		},
+		{
+			"defs are not drawn",
+			`<svg viewBox="0 0 100 100"><defs><rect width="100" height="100" fill="#ff0000"/></defs></svg>`,
+			[]pixel{{50, 50, clear}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := RasterizeSVG(tt.svg, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range tt.pixels {
+				if got := img.RGBAAt(p.x, p.y); got != p.want {
+					t.Errorf("pixel (%d, %d) = %v, want %v", p.x, p.y, got, p.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRasterizeSVGRejectsBadInput(t *testing.T) {
+	for _, svg := range []string{
+		``,
+		`<g viewBox="0 0 10 10"></g>`,
+		`<svg></svg>`,
+		`<svg viewBox="0 0 0 10"></svg>`,
+	} {
+		if _, err := RasterizeSVG(svg, 100); err == nil {
+			t.Errorf("RasterizeSVG(%q) returned no error", svg)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TwistSpec описание индикатора ориентации из параметра запроса
+type TwistSpec struct {
+	Sticker StickerRef // Элемент, на котором рисуется индикатор
+	Kind    string     // cw, ccw — поворот угла, flip — переворот ребра
+	Color   rune       // Цвет индикатора
+	Auto    string     // Тип автоматических индикаторов (all, corners, edges)
+}
+
+// TwistMarker индикатор ориентации, готовый к построению
+type TwistMarker struct {
+	Frame StickerFrame // Положение элемента
+	Kind  string       // cw, ccw или flip
+	Color rune         // Цвет индикатора
+}
+
+var twistStickerRegexp = regexp.MustCompile(`^([UDFBLR])(\d+)$`)
+
+// ParseTwists парсит список индикаторов вида U0-cw,U2-ccw-R,U1-flip,auto.
+// auto вычисляет индикаторы по состоянию кубика, допускает опции corners/edges и цвет
+func ParseTwists(pTwists string) ([]TwistSpec, error) {
+	var specs []TwistSpec
+	if pTwists == "" {
+		return specs, nil
+	}
+
+	for _, pTwist := range strings.Split(pTwists, ",") {
+		parts := strings.Split(pTwist, "-")
+		spec := TwistSpec{Color: 'K'}
+
+		if parts[0] == "auto" {
+			spec.Auto = "all"
+		} else if m := twistStickerRegexp.FindStringSubmatch(parts[0]); m != nil {
+			index, err := strconv.Atoi(m[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid twist marker sticker %q: %w", parts[0], err)
+			}
+			spec.Sticker = StickerRef{Face: rune(m[1][0]), Index: index}
+		} else {
+			return nil, fmt.Errorf("invalid twist marker %q: expected a sticker like U0", pTwist)
+		}
+
+		for _, option := range parts[1:] {
+			switch {
+			case spec.Auto == "" && (option == "cw" || option == "ccw" || option == "flip"):
+				spec.Kind = option
+			case spec.Auto != "" && (option == "all" || option == "corners" || option == "edges"):
+				spec.Auto = option
+			case len(option) == 1 && colorMapRGBA[rune(option[0])] != "":
+				spec.Color = rune(option[0])
+			default:
+				return nil, fmt.Errorf("invalid twist marker option %q", option)
+			}
+		}
+		if spec.Auto == "" && spec.Kind == "" {
+			return nil, fmt.Errorf("twist marker %q has no type (cw, ccw or flip)", pTwist)
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// ResolveTwists разворачивает автоматические индикаторы по состоянию state
+// и переводит ссылки на элементы в положения с помощью функции locate
+func ResolveTwists(pTwists string, state *CubeState, locate func(ref StickerRef) (StickerFrame, bool)) ([]TwistMarker, error) {
+	parsed, err := ParseTwists(pTwists)
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []TwistSpec
+	for _, spec := range parsed {
+		if spec.Auto == "" {
+			specs = append(specs, spec)
+			continue
+		}
+		if state == nil {
+			return nil, fmt.Errorf("automatic twist markers require a cube state (alg or case)")
+		}
+		specs = append(specs, state.OrientationTwists(spec.Auto, spec.Color)...)
+	}
+
+	markers := make([]TwistMarker, 0, len(specs))
+	for _, spec := range specs {
+		frame, ok := locate(spec.Sticker)
+		if !ok {
+			return nil, fmt.Errorf("unknown sticker %c%d for twist marker", spec.Sticker.Face, spec.Sticker.Index)
+		}
+		markers = append(markers, TwistMarker{Frame: frame, Kind: spec.Kind, Color: spec.Color})
+	}
+	return markers, nil
+}
+
+// OrientationTwists вычисляет индикаторы ориентации деталей верхнего слоя:
+// для каждого угла — в какую сторону его нужно повернуть, чтобы верхний цвет оказался сверху,
+// для каждого ребра — нужно ли его перевернуть
+func (s *CubeState) OrientationTwists(kind string, color rune) []TwistSpec {
+	var specs []TwistSpec
+	up := sideNormals[Up]
+
+	for _, st := range s.Stickers {
+		// Наклейка верхнего цвета детали, которая находится в верхнем слое, но смотрит вбок
+		if st.OriginNormal != up || !s.isTop(st.Pos) || st.Normal == up {
+			continue
+		}
+
+		_, r, c := s.stickerIndex(st.Pos, up)
+		spec := TwistSpec{Sticker: StickerRef{Face: 'U', Index: r*s.Size.X + c}, Color: color}
+
+		switch {
+		case s.isCorner(st.Pos) && kind != "edges":
+			// Наклейка на стороне, следующей за верхней по часовой стрелке, поднимается
+			// поворотом угла против часовой стрелки
+			spec.Kind = "cw"
+			if up.cross(st.Normal).dot(st.Pos) < 0 {
+				spec.Kind = "ccw"
+			}
+		case s.isEdge(st.Pos) && kind != "corners":
+			spec.Kind = "flip"
+		default:
+			continue
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs
+}
+
+// cross возвращает векторное произведение
+func (v Vec3) cross(w Vec3) Vec3 {
+	return Vec3{X: v.Y*w.Z - v.Z*w.Y, Y: v.Z*w.X - v.X*w.Z, Z: v.X*w.Y - v.Y*w.X}
+}
+
+// dot возвращает скалярное произведение
+func (v Vec3) dot(w Vec3) int {
+	return v.X*w.X + v.Y*w.Y + v.Z*w.Z
+}
+
+// GenerateTwistMarkers генерирует слой индикаторов ориентации поверх элементов.
+// Индикаторы рисуются в координатах единичного элемента и переносятся на сторону через transform
+func GenerateTwistMarkers(builder *strings.Builder, markers []TwistMarker) {
+	if len(markers) == 0 {
+		return
+	}
+
+	// Начало группы
+	builder.WriteString("\r\n\t<g id=\"twists\">")
+	for i, marker := range markers {
+		var line, head string
+		switch marker.Kind {
+		case "flip":
+			// Двусторонняя стрелка поперёк ребра, направленная к краю стороны
+			a, b := Point{Y: -0.22}, Point{Y: 0.22}
+			if marker.Frame.Row > 0 && marker.Frame.Row < marker.Frame.Rows-1 {
+				a, b = Point{X: -0.22}, Point{X: 0.22}
+			}
+			line = fmt.Sprintf("M%.3f %.3fL%.3f %.3f", a.X, a.Y, b.X, b.Y)
+			head = arrowHead(b, Point{X: b.X - a.X, Y: b.Y - a.Y}) + arrowHead(a, Point{X: a.X - b.X, Y: a.Y - b.Y})
+		default:
+			// Дуга в три четверти окружности с наконечником
+			const radius = 0.2
+			start, end, sweep := -0.75*math.Pi, 0.75*math.Pi, 1
+			if marker.Kind == "ccw" {
+				start, end, sweep = -0.25*math.Pi, -1.75*math.Pi, 0
+			}
+			from := Point{X: radius * math.Cos(start), Y: radius * math.Sin(start)}
+			to := Point{X: radius * math.Cos(end), Y: radius * math.Sin(end)}
+			tangent := Point{X: -math.Sin(end), Y: math.Cos(end)}
+			if sweep == 0 {
+				tangent = Point{X: -tangent.X, Y: -tangent.Y}
+			}
+			line = fmt.Sprintf("M%.3f %.3fA%.2f %.2f 0 1 %d %.3f %.3f", from.X, from.Y, radius, radius, sweep, to.X, to.Y)
+			head = arrowHead(to, tangent)
+		}
+
+		builder.WriteString(fmt.Sprintf("\r\n\t\t<g id=\"twist-%d\" transform=\"%s\">", i+1, marker.Frame.Transform()))
+		builder.WriteString(fmt.Sprintf("<path d=\"%s\" style=\"fill: none; stroke: %s; stroke-width: 0.06; stroke-linecap: round\"/>",
+			line, colorMapRGBA[marker.Color]))
+		builder.WriteString(fmt.Sprintf("<path d=\"%s\" style=\"fill: %s\"/></g>", head, colorMapRGBA[marker.Color]))
+	}
+	// Закрытие группы
+	builder.WriteString("\r\n\t</g>")
+}
+
+// arrowHead возвращает треугольный наконечник в точке tip, направленный по вектору direction
+func arrowHead(tip, direction Point) string {
+	length := math.Hypot(direction.X, direction.Y)
+	dx, dy := direction.X/length, direction.Y/length
+	const size = 0.1
+	back := Point{X: tip.X - dx*size*0.5, Y: tip.Y - dy*size*0.5}
+	return fmt.Sprintf("M%.3f %.3fL%.3f %.3fL%.3f %.3fz",
+		tip.X+dx*size, tip.Y+dy*size,
+		back.X-dy*size*0.8, back.Y+dx*size*0.8,
+		back.X+dy*size*0.8, back.Y-dx*size*0.8)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTwists(t *testing.T) {
+	tests := []struct {
+		twists string
+		want   []TwistSpec
+	}{
+		{"", []TwistSpec(nil)},
+		{"U0-cw,U2-ccw-R,U1-flip", []TwistSpec{
+			{Sticker: StickerRef{'U', 0}, Kind: "cw", Color: 'K'},
+			{Sticker: StickerRef{'U', 2}, Kind: "ccw", Color: 'R'},
+			{Sticker: StickerRef{'U', 1}, Kind: "flip", Color: 'K'},
+		}},
+		{"auto", []TwistSpec{{Auto: "all", Color: 'K'}}},
+		{"auto-corners-G", []TwistSpec{{Auto: "corners", Color: 'G'}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.twists, func(t *testing.T) {
+			got, err := ParseTwists(tt.twists)
+			if err != nil {
+				t.Fatalf("ParseTwists(%q): %v", tt.twists, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTwists(%q) = %+v, want %+v", tt.twists, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTwistsRejectsBadInput(t *testing.T) {
+	for _, twists := range []string{
+		"U0",
+		"U0-spin",
+		"Q0-cw",
+		"U0U1-cw",
+		"U99999999999999999999-cw",
+		"auto-cw",
+		"U0-corners",
+	} {
+		if _, err := ParseTwists(twists); err == nil {
+			t.Errorf("ParseTwists(%q) returned no error", twists)
+		}
+	}
+}
+
+func TestOrientationTwists(t *testing.T) {
+	tests := []struct {
+		name    string
+		alg     string // Случай, который решает алгоритм (применяется обратный)
+		kind    string
+		corners int
+		flips   int
+	}{
+		{"solved", "", "all", 0, 0},
+		{"Sune", "R U R' U R U2 R'", "all", 3, 0},
+		{"Sune edges", "R U R' U R U2 R'", "edges", 0, 0},
+		{"two flipped edges", "F R U R' U' F'", "edges", 0, 2},
+		{"T-perm", "R U R' U' R' F R2 U' R' U' R U R' F'", "all", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := solvedState(t, "3x3x3")
+			if err := state.ApplyCase(tt.alg); err != nil {
+				t.Fatal(err)
+			}
+
+			kinds := make(map[string]int)
+			for _, spec := range state.OrientationTwists(tt.kind, 'K') {
+				kinds[spec.Kind]++
+			}
+			if corners := kinds["cw"] + kinds["ccw"]; corners != tt.corners || kinds["flip"] != tt.flips {
+				t.Errorf("got %v, want %d twisted corners and %d flipped edges", kinds, tt.corners, tt.flips)
+			}
+			// У Суне все три угла повёрнуты в одну сторону
+			if tt.corners == 3 && kinds["cw"] != 3 && kinds["ccw"] != 3 {
+				t.Errorf("Sune corners are twisted in different directions: %v", kinds)
+			}
+		})
+	}
+}